@@ -1,15 +1,22 @@
 package binance
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 // Binance is wrapper for Binance API.
 //
 // Read web documentation for more endpoints descriptions and list of
 // mandatory and optional params. Wrapper is not responsible for client-side
-// validation and only sends requests further.
+// validation and only sends requests further, except for orders validated
+// when service is created WithOrderValidation.
 //
 // For each API-defined enum there's a special type and list of defined
 // enum values to be used.
@@ -24,14 +31,42 @@ type Binance interface {
 	AggTrades(atr AggTradesRequest) ([]*AggTrade, error)
 
 	ExchangeInfo() (*ExchangeInfo, error)
+	// CachedExchangeInfo returns exchange info cached for WithExchangeInfoTTL.
+	CachedExchangeInfo() (*ExchangeInfo, error)
+	// RefreshExchangeInfo fetches exchange info into cache.
+	RefreshExchangeInfo() (*ExchangeInfo, error)
+	// SymbolStatus returns trading status of symbol, e.g. TRADING or HALT,
+	// from cached exchange info.
+	SymbolStatus(symbol string) (string, error)
+	// SymbolsByQuoteAsset returns symbols quoted in quote asset which are
+	// open for trading, from cached exchange info.
+	SymbolsByQuoteAsset(quote string) ([]string, error)
+	// RateLimits returns rate limits reported by cached exchange info,
+	// optionally only those of types.
+	RateLimits(types ...RateLimitType) ([]RateLimit, error)
 
 	HistoricalTrades(htr HistoricalTradesRequest) ([]*HistoricalTrades, error)
 	// Klines returns klines/candlestick data.
 	Klines(kr KlinesRequest) ([]*Kline, error)
+	// UIKlines returns klines/candlestick data optimized for presentation.
+	UIKlines(kr KlinesRequest) ([]*Kline, error)
+	// LatestKline returns the latest closed kline of symbol.
+	LatestKline(symbol string, interval Interval) (*Kline, error)
+	// IterateAggTrades returns iterator of aggregate trades, which requests
+	// them page by page.
+	IterateAggTrades(atr AggTradesRequest) *AggTradesIterator
+	// IterateKlines returns iterator of klines, which requests them page by
+	// page.
+	IterateKlines(kr KlinesRequest) *KlinesIterator
 	// Ticker24 returns 24hr price change statistics.
 	Ticker24(tr TickerRequest) (*Ticker24, error)
+	// Tickers24 returns 24hr price change statistics of Symbols.
+	Tickers24(tr TickerRequest) ([]*Ticker24, error)
 	// TickerAllPrices returns ticker data for symbols.
 	TickerAllPrices() ([]*PriceTicker, error)
+	// NewPriceCache returns cache serving prices of all symbols, which are
+	// fetched at most once per refresh interval.
+	NewPriceCache(refresh time.Duration) *PriceCache
 	// TickerAllBooks returns tickers for all books.
 	TickerAllBooks() ([]*BookTicker, error)
 
@@ -39,14 +74,38 @@ type Binance interface {
 	NewOrder(nor NewOrderRequest) (*ProcessedOrder, error)
 	// NewOrder places testing order.
 	NewOrderTest(nor NewOrderRequest) error
+	// NewOrderTestResult places testing order and returns commission rates
+	// the order would be charged if ComputeCommissionRates is set.
+	NewOrderTestResult(nor NewOrderRequest) (*TestOrderResult, error)
+	// NewOrderBatch places several orders concurrently and returns results
+	// and errors aligned with orders.
+	NewOrderBatch(orders []NewOrderRequest) ([]*ProcessedOrder, []error, error)
 	// QueryOrder returns data about existing order.
 	QueryOrder(qor QueryOrderRequest) (*ExecutedOrder, error)
+	// QueryOrderByClientID returns data about order with client order id.
+	QueryOrderByClientID(symbol, clientOrderID string) (*ExecutedOrder, error)
 	// CancelOrder cancels order.
 	CancelOrder(cor CancelOrderRequest) (*CanceledOrder, error)
+	// CancelOrderByClientID cancels order with client order id.
+	CancelOrderByClientID(symbol, clientOrderID string) (*CanceledOrder, error)
+	// CancelReplaceOrder cancels existing order and places new one atomically.
+	CancelReplaceOrder(crr CancelReplaceRequest) (*CancelReplaceResult, error)
+	// OrderCount returns number of orders placed within interval (e.g. "10S"
+	// or "1D") as reported by the last order-related response.
+	OrderCount(interval string) int
+	// CircuitState returns state of circuit breaker, CircuitClosed if it's not
+	// enabled by WithCircuitBreaker.
+	CircuitState() CircuitState
+	// WithCredentials returns Binance acting on behalf of another API key.
+	WithCredentials(apiKey string, signer Signer) Binance
 	// OpenOrders returns list of open orders.
 	OpenOrders(oor OpenOrdersRequest) ([]*ExecutedOrder, error)
 	// AllOrders returns list of all previous orders.
 	AllOrders(aor AllOrdersRequest) ([]*ExecutedOrder, error)
+	// OpenOCOOrders returns list of open OCO order lists.
+	OpenOCOOrders(oor OpenOCOOrdersRequest) ([]*ListStatus, error)
+	// AllOCOOrders returns list of all previous OCO order lists.
+	AllOCOOrders(aor AllOCOOrdersRequest) ([]*ListStatus, error)
 
 	// Account returns account data.
 	Account(ar AccountRequest) (*Account, error)
@@ -58,19 +117,103 @@ type Binance interface {
 	DepositHistory(hr HistoryRequest) ([]*Deposit, error)
 	// WithdrawHistory lists withdraw data.
 	WithdrawHistory(hr HistoryRequest) ([]*Withdrawal, error)
+	// DepositHistoryRange lists deposit data of period longer than 90 days.
+	DepositHistoryRange(hr HistoryRequest) ([]*Deposit, error)
+	// WithdrawHistoryRange lists withdraw data of period longer than 90 days.
+	WithdrawHistoryRange(hr HistoryRequest) ([]*Withdrawal, error)
 
 	// StartUserDataStream starts stream and returns Stream with ListenKey.
 	StartUserDataStream() (*Stream, error)
+	// StartMarginUserDataStream starts cross margin stream and returns
+	// Stream with ListenKey.
+	StartMarginUserDataStream() (*Stream, error)
+	// StartIsolatedMarginUserDataStream starts isolated margin stream of
+	// symbol and returns Stream with ListenKey.
+	StartIsolatedMarginUserDataStream(symbol string) (*Stream, error)
 	// KeepAliveUserDataStream prolongs stream livespan.
 	KeepAliveUserDataStream(s *Stream) error
 	// CloseUserDataStream closes opened stream.
 	CloseUserDataStream(s *Stream) error
 
+	// Websocket methods return channel of events and done channel. Cancel
+	// service context to stop all streams or call CloseStream to stop one;
+	// done is closed once the reader has exited and the connection is
+	// closed, so it's safe to assume complete teardown after receiving from
+	// it.
 	DepthWebsocket(dwr DepthWebsocketRequest) (chan *DepthEvent, chan struct{}, error)
 	KlineWebsocket(kwr KlineWebsocketRequest) (chan *KlineEvent, chan struct{}, error)
+	// KlinesWithLive returns historical klines followed by live updates.
+	KlinesWithLive(symbol string, interval Interval, lookback int) (chan *KlineEvent, chan struct{}, error)
 	AggTradeWebsocket(twr AggTradeWebsocketRequest) (chan *AggTradeEvent, chan struct{}, error)
 	TradeWebsocket(twr TradeWebsocketRequest) (chan *TradeEvent, chan struct{}, error)
 	UserDataWebsocket(udwr UserDataWebsocketRequest) (chan *AccountEvent, chan struct{}, error)
+	// UserDataStream starts user data stream and keeps it running until
+	// service context is cancelled.
+	UserDataStream() (chan *AccountEvent, chan struct{}, error)
+	// MarginUserDataStream and IsolatedMarginUserDataStream are
+	// UserDataStream of cross and isolated margin account.
+	MarginUserDataStream() (chan *AccountEvent, chan struct{}, error)
+	IsolatedMarginUserDataStream(symbol string) (chan *AccountEvent, chan struct{}, error)
+	// StreamErrors returns channel with *StreamError for every stream stopped
+	// because of read, parse or backfill failure, shortly before its done is
+	// closed.
+	// Errors are dropped when nobody reads the channel.
+	StreamErrors() <-chan error
+	// Streams returns status of every open websocket connection, e.g. for
+	// health checks.
+	Streams() []StreamStatus
+	// CloseStream stops single stream of done, leaving the others running.
+	CloseStream(done <-chan struct{})
+	// Shutdown stops all streams and waits until they're torn down.
+	Shutdown(ctx context.Context) error
+	// NewStreamClient opens websocket connection managing subscribed streams
+	// with SUBSCRIBE and UNSUBSCRIBE requests.
+	NewStreamClient() (StreamClient, error)
+	// NewWSAPIClient opens websocket API connection for placing and querying
+	// orders with lower latency.
+	NewWSAPIClient() (WSAPIClient, error)
+
+	// FuturesExchangeInfo returns USDT-M futures trading rules and symbols.
+	FuturesExchangeInfo() (*ExchangeInfo, error)
+	// FuturesKlines returns USDT-M futures klines/candlestick data.
+	FuturesKlines(kr KlinesRequest) ([]*Kline, error)
+	// FuturesNewOrder places new USDT-M futures order.
+	FuturesNewOrder(fnor FuturesNewOrderRequest) (*FuturesOrder, error)
+	// FuturesAccount returns USDT-M futures account data.
+	FuturesAccount(ar AccountRequest) (*FuturesAccount, error)
+	// FuturesPositionRisk returns current USDT-M futures positions.
+	FuturesPositionRisk(fprr FuturesPositionRiskRequest) ([]*FuturesPosition, error)
+	// FundingRateHistory returns historical USDT-M futures funding rates.
+	FundingRateHistory(frr FundingRateRequest) ([]*FundingRate, error)
+
+	MarkPriceWebsocket(mpwr MarkPriceWebsocketRequest) (chan *MarkPriceEvent, chan struct{}, error)
+
+	// SubAccountList lists sub-accounts of master account.
+	SubAccountList(salr SubAccountListRequest) ([]*SubAccount, error)
+	// SubAccountTransfer moves balance between master and sub-accounts.
+	SubAccountTransfer(satr SubAccountTransferRequest) (*SubAccountTransferResult, error)
+	// SubAccountAssets returns spot balances of sub-account.
+	SubAccountAssets(saar SubAccountAssetsRequest) ([]*Balance, error)
+
+	// LendingProductList lists flexible savings products.
+	LendingProductList(lplr LendingProductListRequest) ([]*LendingProduct, error)
+	// LendingPurchase subscribes amount to flexible savings product.
+	LendingPurchase(lpr LendingPurchaseRequest) (*LendingPurchaseResult, error)
+	// LendingRedeem redeems amount from flexible savings product.
+	LendingRedeem(lrr LendingRedeemRequest) (*LendingRedeemResult, error)
+
+	// AccountSnapshot returns daily snapshots of wallet balances.
+	AccountSnapshot(asr AccountSnapshotRequest) (*AccountSnapshot, error)
+	// UniversalTransfer moves asset between wallets, e.g. from spot to futures.
+	UniversalTransfer(utr UniversalTransferRequest) (*TransferResult, error)
+	// FundingAssets returns funding wallet balances.
+	FundingAssets(far FundingAssetsRequest) ([]*FundingAsset, error)
+	// UserAssets returns spot wallet balances including frozen and
+	// withdrawing amounts.
+	UserAssets(far FundingAssetsRequest) ([]*FundingAsset, error)
+	// DustEstimate returns assets convertible to BNB with BTC and BNB
+	// estimates.
+	DustEstimate() (*DustEstimate, error)
 }
 
 type binance struct {
@@ -88,7 +231,8 @@ func (e Error) Error() string {
 	return fmt.Sprintf("%d: %s", e.Code, e.Message)
 }
 
-// NewBinance returns Binance instance.
+// NewBinance returns Binance instance, which is safe for concurrent use as
+// long as service is.
 func NewBinance(service Service) Binance {
 	return &binance{
 		Service: service,
@@ -106,36 +250,86 @@ func (b *binance) Time() (time.Time, error) {
 }
 
 // OrderBook represents Bids and Asks.
+//
+// Books returned by API have bids sorted by price descending and asks
+// ascending, UpsertBid and UpsertAsk keep them so.
 type OrderBook struct {
 	LastUpdateID int `json:"lastUpdateId"`
 	Bids         []*Order
 	Asks         []*Order
 }
 
+// DepthEvent represents diff of order book.
+//
+// Event covers updates from FirstUpdateID to UpdateID (final update id)
+// inclusive, which is used to order events against OrderBook snapshot.
+// PrevUpdateID is final update id of previous event, sent by futures depth
+// streams only; each event follows the previous one without gap when
+// PrevUpdateID equals previous event's UpdateID. It is zero for spot.
+//
+// Bids and Asks of diff events hold levels with zero Quantity for price
+// levels to be removed, see Removals and ApplyDepthDelta.
+//
+// IsSnapshot reports whether event holds full order book, which replaces
+// local book instead of being applied to it. Diff depth stream sends
+// snapshot only as the first event if requested by WithSnapshot, with Type
+// DepthSnapshotEventType and Time of the moment snapshot was fetched.
 type DepthEvent struct {
 	WSEvent
-	UpdateID int
+	FirstUpdateID int
+	UpdateID      int
+	PrevUpdateID  int
+	IsSnapshot    bool
 	OrderBook
 }
 
+// DepthSnapshotEventType is Type of DepthEvent holding order book snapshot,
+// telling it apart from "depthUpdate" diff events.
+const DepthSnapshotEventType = "depthSnapshot"
+
 // Order represents single order information.
+//
+// RawPrice and RawQuantity hold values as sent by API, so they can be parsed
+// without float64 precision loss, e.g. with decimal package. They're empty
+// for levels set by UpsertBid and UpsertAsk.
 type Order struct {
-	Price    float64
-	Quantity float64
+	Price       float64
+	Quantity    float64
+	RawPrice    string
+	RawQuantity string
 }
 
 // OrderBookRequest represents OrderBook request data.
+//
+// Limit must be one of 5, 10, 20, 50, 100, 500, 1000 or 5000 (zero means
+// API default of 100). Request weight grows with the limit: 1 for limits up
+// to 100, 5 for 500, 10 for 1000 and 50 for 5000.
 type OrderBookRequest struct {
 	Symbol string
 	Limit  int
 }
 
+// orderBookWeights maps allowed OrderBook limits to their request weights.
+var orderBookWeights = map[int]int{
+	5:    1,
+	10:   1,
+	20:   1,
+	50:   1,
+	100:  1,
+	500:  5,
+	1000: 10,
+	5000: 50,
+}
+
 // OrderBook returns list of orders.
 func (b *binance) OrderBook(obr OrderBookRequest) (*OrderBook, error) {
 	return b.Service.OrderBook(obr)
 }
 
 // AggTrade represents aggregated trade.
+//
+// RawPrice and RawQuantity hold Price and Quantity as sent by API, so they
+// can be parsed without float64 precision loss.
 type AggTrade struct {
 	ID             int
 	Price          float64
@@ -145,6 +339,8 @@ type AggTrade struct {
 	Timestamp      time.Time
 	BuyerMaker     bool
 	BestPriceMatch bool
+	RawPrice       string
+	RawQuantity    string
 }
 
 type AggTradeEvent struct {
@@ -166,6 +362,8 @@ func (b *binance) AggTrades(atr AggTradesRequest) ([]*AggTrade, error) {
 	return b.Service.AggTrades(atr)
 }
 
+// HistoricalTradesRequest represents HistoricalTrades request data. Zero
+// FromId returns the most recent trades, zero Limit defaults to 500.
 type HistoricalTradesRequest struct {
 	Symbol string
 	Limit  int
@@ -205,13 +403,9 @@ type Symbol struct {
 }
 
 type ExchangeInfo struct {
-	TimeZone   string `json:"timezone"`
-	ServerTime uint   `json:"serverTime"`
-	RateLimits []struct {
-		RateLimitType string `json:"rateLimitType"`
-		Interval      string `json:"interval"`
-		Limit         int    `json:"limit"`
-	} `json:"rateLimits"`
+	TimeZone        string        `json:"timezone"`
+	ServerTime      uint          `json:"serverTime"`
+	RateLimits      []RateLimit   `json:"rateLimits"`
 	ExchangeFilters []interface{} `json:"exchangeFilters"`
 	Symbols         []Symbol      `json:"symbols"`
 }
@@ -220,6 +414,19 @@ func (b *binance) ExchangeInfo() (*ExchangeInfo, error) {
 	return b.Service.ExchangeInfo()
 }
 
+// CachedExchangeInfo returns exchange info cached for TTL set by
+// WithExchangeInfoTTL, fetching it when it's missing or expired. Cache is
+// shared by instances returned by WithCredentials.
+func (b *binance) CachedExchangeInfo() (*ExchangeInfo, error) {
+	return b.Service.CachedExchangeInfo()
+}
+
+// RefreshExchangeInfo fetches exchange info into cache regardless of TTL,
+// e.g. once symbol filters are known to change.
+func (b *binance) RefreshExchangeInfo() (*ExchangeInfo, error) {
+	return b.Service.RefreshExchangeInfo()
+}
+
 type Trade struct {
 	ID             uint64
 	Price          float64
@@ -231,6 +438,9 @@ type Trade struct {
 	BestPriceMatch bool
 }
 
+// TradeEventResponse represents raw trade stream event. BuyerId and SellerId
+// are ids of buyer's ("b") and seller's ("a") orders, they may be zero since
+// Binance no longer sends them.
 type TradeEventResponse struct {
 	Type          string  `json:"e"`
 	EventTime     int64   `json:"E"`
@@ -242,6 +452,7 @@ type TradeEventResponse struct {
 	SellerId      uint64  `json:"a"`
 	TradeTime     int64   `json:"T"`
 	IsMarketMaker bool    `json:"m"`
+	BestMatch     bool    `json:"M"`
 }
 
 type TradeEvent struct {
@@ -259,15 +470,22 @@ type TradesRequest struct {
 }
 
 // KlinesRequest represents Klines request data.
+//
+// The last kline returned for live interval is still in progress, ClosedOnly
+// drops it so only closed klines are returned.
 type KlinesRequest struct {
-	Symbol    string
-	Interval  Interval
-	Limit     int
-	StartTime int64
-	EndTime   int64
+	Symbol     string
+	Interval   Interval
+	Limit      int
+	StartTime  int64
+	EndTime    int64
+	ClosedOnly bool
 }
 
 // Kline represents single Kline information.
+//
+// Fields prefixed with Raw hold values of the same named fields as sent by
+// API, so they can be parsed without float64 precision loss.
 type Kline struct {
 	OpenTime                 time.Time
 	Open                     float64
@@ -280,6 +498,15 @@ type Kline struct {
 	NumberOfTrades           int
 	TakerBuyBaseAssetVolume  float64
 	TakerBuyQuoteAssetVolume float64
+
+	RawOpen                     string
+	RawHigh                     string
+	RawLow                      string
+	RawClose                    string
+	RawVolume                   string
+	RawQuoteAssetVolume         string
+	RawTakerBuyBaseAssetVolume  string
+	RawTakerBuyQuoteAssetVolume string
 }
 
 type KlineEvent struct {
@@ -296,13 +523,54 @@ func (b *binance) Klines(kr KlinesRequest) ([]*Kline, error) {
 	return b.Service.Klines(kr)
 }
 
+// UIKlines returns klines/candlestick data optimized for presentation of
+// candlestick charts.
+func (b *binance) UIKlines(kr KlinesRequest) ([]*Kline, error) {
+	return b.Service.UIKlines(kr)
+}
+
+// LatestKline returns the latest closed kline of symbol, skipping the kline
+// still in progress.
+func (b *binance) LatestKline(symbol string, interval Interval) (*Kline, error) {
+	klines, err := b.Service.Klines(KlinesRequest{
+		Symbol:     symbol,
+		Interval:   interval,
+		Limit:      2,
+		ClosedOnly: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(klines) == 0 {
+		return nil, errors.Errorf("no closed kline of %s %s", symbol, interval)
+	}
+	return klines[len(klines)-1], nil
+}
+
+// TickerType represents ticker type enum.
+type TickerType string
+
+var (
+	TickerFull = TickerType("FULL")
+	TickerMini = TickerType("MINI")
+)
+
 // TickerRequest represents Ticker request data.
+//
+// TickerMini Type has lower weight and returns OHLCV only, leaving
+// PriceChange, PriceChangePercent, WeightedAvgPrice, PrevClosePrice,
+// BidPrice and AskPrice zero. Default type is TickerFull.
+//
+// Symbol is used by Ticker24, Symbols by Tickers24.
 type TickerRequest struct {
-	Symbol string
+	Symbol  string
+	Symbols []string
+	Type    TickerType
 }
 
 // Ticker24 represents data for 24hr ticker.
 type Ticker24 struct {
+	Symbol             string
 	PriceChange        float64
 	PriceChangePercent float64
 	WeightedAvgPrice   float64
@@ -326,6 +594,12 @@ func (b *binance) Ticker24(tr TickerRequest) (*Ticker24, error) {
 	return b.Service.Ticker24(tr)
 }
 
+// Tickers24 returns 24hr price change statistics of several symbols in one
+// request, which weighs less than requesting them one by one.
+func (b *binance) Tickers24(tr TickerRequest) ([]*Ticker24, error) {
+	return b.Service.Tickers24(tr)
+}
+
 // PriceTicker represents ticker data for price.
 type PriceTicker struct {
 	Symbol string
@@ -352,6 +626,23 @@ func (b *binance) TickerAllBooks() ([]*BookTicker, error) {
 }
 
 // NewOrderRequest represents NewOrder request data.
+//
+// Fields required besides Symbol, Side and Quantity depend on Type:
+//
+//	LIMIT              Price, TimeInForce
+//	MARKET             none
+//	LIMIT_MAKER        Price
+//	STOP_LOSS          StopPrice and/or TrailingDelta
+//	TAKE_PROFIT        StopPrice and/or TrailingDelta
+//	STOP_LOSS_LIMIT    Price, StopPrice and/or TrailingDelta, TimeInForce
+//	TAKE_PROFIT_LIMIT  Price, StopPrice and/or TrailingDelta, TimeInForce
+//
+// TrailingDelta in basis points makes stop order trailing, it's triggered
+// once price moves by the delta against the best price reached since the
+// order was placed, or since StopPrice was reached if it's set.
+//
+// Zero Price, TimeInForce, StopPrice and TrailingDelta are not sent. See
+// Validate.
 type NewOrderRequest struct {
 	Symbol           string
 	Side             OrderSide
@@ -361,11 +652,18 @@ type NewOrderRequest struct {
 	Price            float64
 	NewClientOrderID string
 	StopPrice        float64
+	TrailingDelta    int
 	IcebergQty       float64
 	Timestamp        time.Time
+	// ComputeCommissionRates requests commission rates of testing order,
+	// it's ignored when order is placed.
+	ComputeCommissionRates bool
 }
 
 // ProcessedOrder represents data from processed order.
+//
+// ClientOrderID is the one order was placed with, either provided by request
+// or generated by service, see WithClientOrderIDs.
 type ProcessedOrder struct {
 	Symbol        string
 	OrderID       int64
@@ -383,6 +681,75 @@ func (b *binance) NewOrderTest(nor NewOrderRequest) error {
 	return b.Service.NewOrderTest(nor)
 }
 
+// TestOrderResult represents data computed for testing order. It's empty
+// unless ComputeCommissionRates of the request is set.
+type TestOrderResult struct {
+	StandardCommissionForOrder CommissionRates
+	TaxCommissionForOrder      CommissionRates
+	Discount                   CommissionDiscount
+}
+
+// CommissionDiscount represents discount of commission paid with
+// DiscountAsset, e.g. 0.25 for 25% off.
+type CommissionDiscount struct {
+	EnabledForAccount bool
+	EnabledForSymbol  bool
+	DiscountAsset     string
+	Discount          float64
+}
+
+// CommissionRates represents maker and taker commission rates, e.g. 0.001
+// for 0.1%.
+type CommissionRates struct {
+	Maker float64
+	Taker float64
+}
+
+// NewOrderTestResult places testing order and returns commission rates the
+// order would be charged if ComputeCommissionRates is set, so order can be
+// validated and fees previewed with one call.
+func (b *binance) NewOrderTestResult(nor NewOrderRequest) (*TestOrderResult, error) {
+	return b.Service.NewOrderTestResult(nor)
+}
+
+// newOrderBatchConcurrency limits number of NewOrderBatch requests in flight.
+const newOrderBatchConcurrency = 5
+
+// NewOrderBatch places several orders concurrently.
+//
+// Spot API has no batch endpoint, so orders are sent as separate requests
+// with at most newOrderBatchConcurrency of them in flight. Returned results
+// and errors are aligned with orders, so successful placements are kept on
+// partial failure. Error is returned if any of orders failed.
+func (b *binance) NewOrderBatch(orders []NewOrderRequest) ([]*ProcessedOrder, []error, error) {
+	results := make([]*ProcessedOrder, len(orders))
+	errs := make([]error, len(orders))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, newOrderBatchConcurrency)
+	for i := range orders {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i], errs[i] = b.Service.NewOrder(orders[i])
+		}(i)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return results, errs, errors.Errorf("%d of %d orders failed", failed, len(orders))
+	}
+	return results, errs, nil
+}
+
 // QueryOrderRequest represents QueryOrder request data.
 type QueryOrderRequest struct {
 	Symbol            string
@@ -393,6 +760,10 @@ type QueryOrderRequest struct {
 }
 
 // ExecutedOrder represents data about executed order.
+//
+// CumQuoteQty is cumulative quote quantity of fills, so average fill price
+// is CumQuoteQty / ExecutedQty. IsWorking reports whether order is on the
+// book, e.g. stop orders aren't until triggered.
 type ExecutedOrder struct {
 	Symbol        string
 	OrderID       int
@@ -400,6 +771,7 @@ type ExecutedOrder struct {
 	Price         float64
 	OrigQty       float64
 	ExecutedQty   float64
+	CumQuoteQty   float64
 	Status        OrderStatus
 	TimeInForce   TimeInForce
 	Type          OrderType
@@ -407,6 +779,8 @@ type ExecutedOrder struct {
 	StopPrice     float64
 	IcebergQty    float64
 	Time          time.Time
+	UpdateTime    time.Time
+	IsWorking     bool
 }
 
 // QueryOrder returns data about existing order.
@@ -414,14 +788,38 @@ func (b *binance) QueryOrder(qor QueryOrderRequest) (*ExecutedOrder, error) {
 	return b.Service.QueryOrder(qor)
 }
 
+// QueryOrderByClientID returns data about order identified by client order
+// id, i.e. NewClientOrderID it was placed with.
+func (b *binance) QueryOrderByClientID(symbol, clientOrderID string) (*ExecutedOrder, error) {
+	return b.Service.QueryOrder(QueryOrderRequest{
+		Symbol:            symbol,
+		OrigClientOrderID: clientOrderID,
+		Timestamp:         time.Now(),
+	})
+}
+
+// CancelRestrictions represents condition order has to meet to be canceled.
+type CancelRestrictions string
+
+var (
+	// OnlyNew cancels order only if it's NEW, i.e. wasn't filled at all.
+	OnlyNew = CancelRestrictions("ONLY_NEW")
+	// OnlyPartiallyFilled cancels order only if it's PARTIALLY_FILLED.
+	OnlyPartiallyFilled = CancelRestrictions("ONLY_PARTIALLY_FILLED")
+)
+
 // CancelOrderRequest represents CancelOrder request data.
+//
+// CancelRestrictions makes cancel fail if order's status changed, e.g. order
+// was partially filled just before canceling it with OnlyNew.
 type CancelOrderRequest struct {
-	Symbol            string
-	OrderID           int64
-	OrigClientOrderID string
-	NewClientOrderID  string
-	RecvWindow        time.Duration
-	Timestamp         time.Time
+	Symbol             string
+	OrderID            int64
+	OrigClientOrderID  string
+	NewClientOrderID   string
+	CancelRestrictions CancelRestrictions
+	RecvWindow         time.Duration
+	Timestamp          time.Time
 }
 
 // CanceledOrder represents data about canceled order.
@@ -437,6 +835,85 @@ func (b *binance) CancelOrder(cor CancelOrderRequest) (*CanceledOrder, error) {
 	return b.Service.CancelOrder(cor)
 }
 
+// CancelOrderByClientID cancels order identified by client order id, i.e.
+// NewClientOrderID it was placed with.
+func (b *binance) CancelOrderByClientID(symbol, clientOrderID string) (*CanceledOrder, error) {
+	return b.Service.CancelOrder(CancelOrderRequest{
+		Symbol:            symbol,
+		OrigClientOrderID: clientOrderID,
+		Timestamp:         time.Now(),
+	})
+}
+
+// CancelReplaceMode represents cancelReplaceMode enum.
+type CancelReplaceMode string
+
+var (
+	// StopOnFailure doesn't place new order if cancel fails.
+	StopOnFailure = CancelReplaceMode("STOP_ON_FAILURE")
+	// AllowFailure places new order even if cancel fails.
+	AllowFailure = CancelReplaceMode("ALLOW_FAILURE")
+)
+
+// CancelReplaceRequest represents CancelReplaceOrder request data.
+//
+// Embedded NewOrderRequest describes order to place, order to cancel is
+// identified by CancelOrderID or CancelOrigClientOrderID and is canceled
+// only if it meets CancelRestrictions, if set.
+type CancelReplaceRequest struct {
+	NewOrderRequest
+	CancelReplaceMode       CancelReplaceMode
+	CancelOrderID           int64
+	CancelOrigClientOrderID string
+	CancelNewClientOrderID  string
+	CancelRestrictions      CancelRestrictions
+	RecvWindow              time.Duration
+}
+
+// CancelReplaceResult represents result of CancelReplaceOrder.
+//
+// CancelResult and NewOrderResult are SUCCESS, FAILURE or NOT_ATTEMPTED.
+// Response of failed operation is nil and its error is set instead.
+type CancelReplaceResult struct {
+	CancelResult     string
+	NewOrderResult   string
+	CancelResponse   *CanceledOrder
+	CancelError      *Error
+	NewOrderResponse *ProcessedOrder
+	NewOrderError    *Error
+}
+
+// CancelReplaceOrder cancels existing order and places new one atomically.
+//
+// If any of operations fails, both result and error are returned, so that
+// outcome of the other one is available.
+func (b *binance) CancelReplaceOrder(crr CancelReplaceRequest) (*CancelReplaceResult, error) {
+	return b.Service.CancelReplaceOrder(crr)
+}
+
+// OrderCount returns number of orders placed within interval as reported
+// by X-MBX-ORDER-COUNT-* headers of the last order-related response.
+//
+// Interval is case-insensitive and consists of number and unit, e.g. "10S"
+// or "1D". Zero is returned if count for interval wasn't reported yet.
+func (b *binance) OrderCount(interval string) int {
+	return b.Service.OrderCount(interval)
+}
+
+// CircuitState returns state of circuit breaker, e.g. to report degraded
+// service while it's not CircuitClosed.
+func (b *binance) CircuitState() CircuitState {
+	return b.Service.CircuitState()
+}
+
+// WithCredentials returns Binance acting on behalf of another API key, e.g.
+// for multi-account bots. Returned instance shares configuration and
+// connections, including cached exchange info, with the original one and
+// tracks its own OrderCount.
+func (b *binance) WithCredentials(apiKey string, signer Signer) Binance {
+	return NewBinance(b.Service.WithCredentials(apiKey, signer))
+}
+
 // OpenOrdersRequest represents OpenOrders request data.
 type OpenOrdersRequest struct {
 	Symbol     string
@@ -453,6 +930,8 @@ func (b *binance) OpenOrders(oor OpenOrdersRequest) ([]*ExecutedOrder, error) {
 type AllOrdersRequest struct {
 	Symbol     string
 	OrderID    int64
+	StartTime  time.Time
+	EndTime    time.Time
 	Limit      int
 	RecvWindow time.Duration
 	Timestamp  time.Time
@@ -463,12 +942,43 @@ func (b *binance) AllOrders(aor AllOrdersRequest) ([]*ExecutedOrder, error) {
 	return b.Service.AllOrders(aor)
 }
 
-// AccountRequest represents Account request data.
-type AccountRequest struct {
+// OpenOCOOrdersRequest represents OpenOCOOrders request data.
+type OpenOCOOrdersRequest struct {
+	RecvWindow time.Duration
+	Timestamp  time.Time
+}
+
+// OpenOCOOrders returns list of open OCO order lists.
+func (b *binance) OpenOCOOrders(oor OpenOCOOrdersRequest) ([]*ListStatus, error) {
+	return b.Service.OpenOCOOrders(oor)
+}
+
+// AllOCOOrdersRequest represents AllOCOOrders request data.
+//
+// FromID can't be combined with StartTime and EndTime.
+type AllOCOOrdersRequest struct {
+	FromID     int64
+	StartTime  time.Time
+	EndTime    time.Time
+	Limit      int
 	RecvWindow time.Duration
 	Timestamp  time.Time
 }
 
+// AllOCOOrders returns list of all previous OCO order lists.
+func (b *binance) AllOCOOrders(aor AllOCOOrdersRequest) ([]*ListStatus, error) {
+	return b.Service.AllOCOOrders(aor)
+}
+
+// AccountRequest represents Account request data.
+//
+// OmitZeroBalances leaves out assets with both free and locked amount zero.
+type AccountRequest struct {
+	RecvWindow       time.Duration
+	Timestamp        time.Time
+	OmitZeroBalances bool
+}
+
 // Account represents user's account information.
 type Account struct {
 	MakerCommision  int64
@@ -481,9 +991,95 @@ type Account struct {
 	Balances        []*Balance
 }
 
+// Balance returns balance of asset, e.g. USDT, and whether account has it.
+func (a *Account) Balance(asset string) (*Balance, bool) {
+	asset = strings.ToUpper(asset)
+	for _, b := range a.Balances {
+		if b != nil && b.Asset == asset {
+			return b, true
+		}
+	}
+	return nil, false
+}
+
+// NonZeroBalances returns balances of assets with non-zero free or locked
+// amount.
+func (a *Account) NonZeroBalances() []*Balance {
+	var balances []*Balance
+	for _, b := range a.Balances {
+		if b != nil && (b.Free != 0 || b.Locked != 0) {
+			balances = append(balances, b)
+		}
+	}
+	return balances
+}
+
+// Free returns free amount of asset, zero if account doesn't have it.
+func (a *Account) Free(asset string) float64 {
+	if b, ok := a.Balance(asset); ok {
+		return b.Free
+	}
+	return 0
+}
+
+// AccountEvent represents user data stream event.
+//
+// Account is filled by legacy outboundAccountInfo event, other events fill
+// their own field and leave the rest nil. Binance replaced outboundAccountInfo
+// with outboundAccountPosition, which carries changed balances only.
+//
+// ListenKeyExpired is set once listen key of the stream expired, no more
+// events are delivered by the stream then and it has to be restarted with
+// fresh listen key, which UserDataStream does itself.
 type AccountEvent struct {
 	WSEvent
 	Account
+	ListStatus       *ListStatus
+	BalanceUpdate    *BalanceUpdate
+	AccountPosition  *AccountPosition
+	ExecutionReport  *ExecutionReportEvent
+	ListenKeyExpired *ListenKeyExpired
+}
+
+// ListenKeyExpired represents listenKeyExpired event of user data stream.
+type ListenKeyExpired struct {
+	ListenKey string
+}
+
+// AccountPosition represents balances changed by account update, reported
+// by outboundAccountPosition event.
+type AccountPosition struct {
+	LastUpdateTime time.Time
+	Balances       []*Balance
+}
+
+// ListStatus represents status of order list (e.g. OCO) reported by
+// listStatus event or returned by order list queries.
+type ListStatus struct {
+	Symbol            string
+	OrderListID       int64
+	ContingencyType   string
+	ListStatusType    string
+	ListOrderStatus   string
+	ListRejectReason  string
+	ListClientOrderID string
+	TransactionTime   time.Time
+	Orders            []*ListStatusOrder
+}
+
+// BalanceUpdate represents balance change made outside of trading, e.g. by
+// deposit, withdrawal or transfer, reported by balanceUpdate event.
+type BalanceUpdate struct {
+	Asset     string
+	Delta     float64
+	ClearTime time.Time
+}
+
+// ListStatusOrder identifies order belonging to order list.
+type ListStatusOrder struct {
+	Symbol        string
+	OrderID       int64
+	ClientOrderID string
 }
 
 type OutboundAccountInfoEvent struct {
@@ -500,6 +1096,8 @@ type OutboundAccountInfoEvent struct {
 	Balances          []*Balance `json:"B"`
 }
 
+// ExecutionReportEvent represents executionReport event of user data stream.
+// OrderListID is -1 unless order belongs to order list, e.g. OCO.
 type ExecutionReportEvent struct {
 	Type                     string  `json:"e"`        //"e": "executionReport",
 	EventTime                int64   `json:"E"`        //"E": 1530729058977,
@@ -524,20 +1122,105 @@ type ExecutionReportEvent struct {
 	CommissionAsset          string  `json:"N"`        //"N": null,
 	TransactionTime          int64   `json:"T"`        //"T": 1530729058976,
 	TradeId                  int64   `json:"t"`        //"t": -1,
-	w                        bool    `json:"w"`        //"w": true,
-	m                        bool    `json:"m"`        //"m": false,
+	IsWorking                bool    `json:"w"`        //"w": true,
+	IsMaker                  bool    `json:"m"`        //"m": false,
 	M                        bool    `json:"M"`        //"M": false,
 	O                        int64   `json:"O"`        //"O": 1530729058976,
 	Z                        float64 `json:"Z,string"` //"Z": "0.00000000",
-	//"g": -1,         - ignored
+	OrderListID              int64   `json:"g"`        //"g": -1,
 	//"I": 421966584,  - ignored
 }
 
+type executionReportEvent ExecutionReportEvent
+
+// UnmarshalJSON decodes executionReport event. CommissionAmount is accepted
+// both as string and number, null or empty commission is zero.
+func (e *ExecutionReportEvent) UnmarshalJSON(data []byte) error {
+	raw := &struct {
+		*executionReportEvent
+		CommissionAmount json.RawMessage `json:"n"`
+		// Keys are matched case-insensitively, so keys not parsed otherwise
+		// are consumed here instead of overwriting "i", "q" and "w".
+		Ignore        json.RawMessage `json:"I"`
+		QuoteOrderQty json.RawMessage `json:"Q"`
+		WorkingTime   json.RawMessage `json:"W"`
+	}{executionReportEvent: (*executionReportEvent)(e)}
+	if err := json.Unmarshal(data, raw); err != nil {
+		return err
+	}
+	commission, err := lenientFloat(raw.CommissionAmount)
+	if err != nil {
+		return errors.Wrap(err, "commission amount parse failed")
+	}
+	e.CommissionAmount = commission
+	return nil
+}
+
+// ToExecutedOrder returns order state reported by event in the form returned
+// by QueryOrder, so the same code can handle orders of both. ClientOrderID is
+// the original one also for canceled orders.
+func (e ExecutionReportEvent) ToExecutedOrder() *ExecutedOrder {
+	clientOrderID := e.ClientOrderId
+	if e.OriginalClientOrderID != "" {
+		clientOrderID = e.OriginalClientOrderID
+	}
+	return &ExecutedOrder{
+		Symbol:        e.Symbol,
+		OrderID:       int(e.OrderId),
+		ClientOrderID: clientOrderID,
+		Price:         e.Price,
+		OrigQty:       e.Quantity,
+		ExecutedQty:   e.CumulativeFilledQuantity,
+		CumQuoteQty:   e.Z,
+		Status:        OrderStatus(e.CurrentOrderStatus),
+		TimeInForce:   TimeInForce(e.TimeInForce),
+		Type:          OrderType(e.OrderType),
+		Side:          OrderSide(e.Side),
+		StopPrice:     e.StopPrice,
+		IcebergQty:    e.IcebergQty,
+		Time:          timeFromUnixMillis(e.O),
+		UpdateTime:    timeFromUnixMillis(e.TransactionTime),
+		IsWorking:     e.IsWorking,
+	}
+}
+
 // Balance groups balance-related information.
+//
+// RawFree and RawLocked hold Free and Locked as sent by API, so they can be
+// parsed without float64 precision loss. They're empty for balances which
+// API sends as numbers.
 type Balance struct {
-	Asset  string  `json:"a"`
-	Free   float64 `json:"f,string"`
-	Locked float64 `json:"l,string"`
+	Asset     string  `json:"a"`
+	Free      float64 `json:"f,string"`
+	Locked    float64 `json:"l,string"`
+	RawFree   string  `json:"-"`
+	RawLocked string  `json:"-"`
+}
+
+type balance Balance
+
+// UnmarshalJSON parses balance of user data stream event, keeping raw
+// values of Free and Locked.
+func (b *Balance) UnmarshalJSON(data []byte) error {
+	raw := &struct {
+		*balance
+		Free   string `json:"f"`
+		Locked string `json:"l"`
+	}{balance: (*balance)(b)}
+	if err := json.Unmarshal(data, raw); err != nil {
+		return err
+	}
+	free, err := floatFromString(raw.Free)
+	if err != nil {
+		return errors.Wrap(err, "cannot parse Balance.Free")
+	}
+	locked, err := floatFromString(raw.Locked)
+	if err != nil {
+		return errors.Wrap(err, "cannot parse Balance.Locked")
+	}
+	b.Free, b.RawFree = free, raw.Free
+	b.Locked, b.RawLocked = locked, raw.Locked
+	return nil
 }
 
 // Account returns account data.
@@ -546,15 +1229,24 @@ func (b *binance) Account(ar AccountRequest) (*Account, error) {
 }
 
 // MyTradesRequest represents MyTrades request data.
+//
+// OrderID limits trades to fills of single order, FromID returns trades
+// starting with given trade id.
 type MyTradesRequest struct {
 	Symbol     string
 	Limit      int
+	OrderID    int64
 	FromID     int64
+	StartTime  time.Time
+	EndTime    time.Time
 	RecvWindow time.Duration
 	Timestamp  time.Time
 }
 
 // Trade represents data about trade.
+//
+// RawPrice, RawQty and RawCommission hold Price, Qty and Commission as sent
+// by API, so they can be parsed without float64 precision loss.
 type MyTrade struct {
 	ID              int64
 	Price           float64
@@ -565,6 +1257,9 @@ type MyTrade struct {
 	IsBuyer         bool
 	IsMaker         bool
 	IsBestMatch     bool
+	RawPrice        string
+	RawQty          string
+	RawCommission   string
 }
 
 // MyTrades list user's trades.
@@ -572,7 +1267,30 @@ func (b *binance) MyTrades(mtr MyTradesRequest) ([]*MyTrade, error) {
 	return b.Service.MyTrades(mtr)
 }
 
+// AverageFillPrice computes volume weighted average price, total quantity and
+// total commission of trades, e.g. fills of single order returned by MyTrades.
+//
+// Commissions are summed as they are, so trades should share CommissionAsset.
+// Zero average price is returned if there is no traded quantity.
+func AverageFillPrice(trades []*MyTrade) (avgPrice, totalQty, totalCommission float64) {
+	var notional, qty, commission kahanSum
+	for _, t := range trades {
+		if t == nil {
+			continue
+		}
+		notional.add(t.Price * t.Qty)
+		qty.add(t.Qty)
+		commission.add(t.Commission)
+	}
+	if qty.sum != 0 {
+		avgPrice = notional.sum / qty.sum
+	}
+	return avgPrice, qty.sum, commission.sum
+}
+
 // WithdrawRequest represents Withdraw request data.
+//
+// Asset is sent as coin.
 type WithdrawRequest struct {
 	Asset      string
 	Address    string
@@ -583,7 +1301,12 @@ type WithdrawRequest struct {
 }
 
 // WithdrawResult represents Withdraw result.
+//
+// ID identifies withdrawal in WithdrawHistory. Success is set for every
+// accepted withdrawal, rejected ones are returned as *Error. Msg is no
+// longer sent by Binance and is left empty.
 type WithdrawResult struct {
+	ID      string
 	Success bool
 	Msg     string
 }
@@ -594,20 +1317,82 @@ func (b *binance) Withdraw(wr WithdrawRequest) (*WithdrawResult, error) {
 }
 
 // HistoryRequest represents history-related calls request data.
+//
+// Asset is sent as coin, Limit is at most 1000 and Offset skips records of
+// the period, newest first.
 type HistoryRequest struct {
 	Asset      string
 	Status     *int
 	StartTime  time.Time
 	EndTime    time.Time
+	Limit      int
+	Offset     int
 	RecvWindow time.Duration
 	Timestamp  time.Time
 }
 
+// historyWindow is the longest period history is returned for by one call.
+const historyWindow = 90 * 24 * time.Hour
+
+// historyPageSize is the page size historyRange uses when request doesn't
+// set Limit, it's the largest one accepted by history endpoints.
+const historyPageSize = 1000
+
+// historyRange calls fetch for every historyWindow between StartTime and
+// EndTime of hr, paging by Offset within window with Limit or
+// historyPageSize records per page. Fetch returns number of received records
+// and number of those not received before. A full page without new record
+// means server ignored Offset and is returned as error, as remaining records
+// of window can't be reached.
+func historyRange(hr HistoryRequest, fetch func(hr HistoryRequest) (n int, fresh int, err error)) error {
+	if hr.StartTime.IsZero() || hr.EndTime.IsZero() {
+		return errors.New("StartTime and EndTime are required")
+	}
+	if hr.Limit == 0 {
+		hr.Limit = historyPageSize
+	}
+	for start := hr.StartTime; !start.After(hr.EndTime); {
+		// both ends of window are inclusive
+		end := start.Add(historyWindow - time.Millisecond)
+		if end.After(hr.EndTime) {
+			end = hr.EndTime
+		}
+		whr := hr
+		whr.StartTime = start
+		whr.EndTime = end
+		whr.Offset = 0
+		for {
+			whr.Timestamp = time.Now()
+			n, fresh, err := fetch(whr)
+			if err != nil {
+				return err
+			}
+			if n < hr.Limit {
+				break
+			}
+			if fresh == 0 {
+				return errors.Errorf("history page at offset %d repeats received records, offset is ignored",
+					whr.Offset)
+			}
+			whr.Offset += n
+		}
+		start = end.Add(time.Millisecond)
+	}
+	return nil
+}
+
 // Deposit represents Deposit data.
+//
+// Status is 0 for pending, 6 for credited but not yet withdrawable and 1 for
+// successful deposit.
 type Deposit struct {
+	ID         string
 	InsertTime time.Time
 	Amount     float64
 	Asset      string
+	Network    string
+	Address    string
+	TxID       string
 	Status     int
 }
 
@@ -616,14 +1401,48 @@ func (b *binance) DepositHistory(hr HistoryRequest) ([]*Deposit, error) {
 	return b.Service.DepositHistory(hr)
 }
 
+// DepositHistoryRange lists deposit data between StartTime and EndTime of
+// hr, which may span more than 90 days allowed by single DepositHistory call.
+// Timestamp of every call is set to current time and records repeated across
+// pages are returned once.
+func (b *binance) DepositHistoryRange(hr HistoryRequest) ([]*Deposit, error) {
+	var dc []*Deposit
+	seen := make(map[string]bool)
+	err := historyRange(hr, func(hr HistoryRequest) (int, int, error) {
+		d, err := b.Service.DepositHistory(hr)
+		fresh := 0
+		for _, deposit := range d {
+			if deposit.ID != "" {
+				if seen[deposit.ID] {
+					continue
+				}
+				seen[deposit.ID] = true
+			}
+			dc = append(dc, deposit)
+			fresh++
+		}
+		return len(d), fresh, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dc, nil
+}
+
 // Withdrawal represents withdrawal data.
+//
+// Status is 0 for email sent, 1 cancelled, 2 awaiting approval, 3 rejected,
+// 4 processing, 5 failure and 6 completed.
 type Withdrawal struct {
-	Amount    float64
-	Address   string
-	TxID      string
-	Asset     string
-	ApplyTime time.Time
-	Status    int
+	ID             string
+	Amount         float64
+	TransactionFee float64
+	Address        string
+	TxID           string
+	Asset          string
+	Network        string
+	ApplyTime      time.Time
+	Status         int
 }
 
 // WithdrawHistory lists withdraw data.
@@ -631,11 +1450,57 @@ func (b *binance) WithdrawHistory(hr HistoryRequest) ([]*Withdrawal, error) {
 	return b.Service.WithdrawHistory(hr)
 }
 
+// WithdrawHistoryRange lists withdraw data between StartTime and EndTime of
+// hr, which may span more than 90 days allowed by single WithdrawHistory call.
+// Timestamp of every call is set to current time and records repeated across
+// pages are returned once.
+func (b *binance) WithdrawHistoryRange(hr HistoryRequest) ([]*Withdrawal, error) {
+	var wc []*Withdrawal
+	seen := make(map[string]bool)
+	err := historyRange(hr, func(hr HistoryRequest) (int, int, error) {
+		w, err := b.Service.WithdrawHistory(hr)
+		fresh := 0
+		for _, withdrawal := range w {
+			if withdrawal.ID != "" {
+				if seen[withdrawal.ID] {
+					continue
+				}
+				seen[withdrawal.ID] = true
+			}
+			wc = append(wc, withdrawal)
+			fresh++
+		}
+		return len(w), fresh, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return wc, nil
+}
+
 // Stream represents stream information.
 //
 // Read web docs to get more information about using streams.
+//
+// Margin is set for cross margin stream and Symbol for isolated margin one,
+// both are zero for spot stream. They select listen key endpoints used by
+// KeepAliveUserDataStream and CloseUserDataStream.
 type Stream struct {
 	ListenKey string
+	Margin    bool   `json:"-"`
+	Symbol    string `json:"-"`
+}
+
+// endpoint returns listen key endpoint of the kind of s.
+func (s *Stream) endpoint() string {
+	switch {
+	case s.Symbol != "":
+		return "sapi/v1/userDataStream/isolated"
+	case s.Margin:
+		return "sapi/v1/userDataStream"
+	default:
+		return "api/v1/userDataStream"
+	}
 }
 
 // StartUserDataStream starts stream and returns Stream with ListenKey.
@@ -643,6 +1508,18 @@ func (b *binance) StartUserDataStream() (*Stream, error) {
 	return b.Service.StartUserDataStream()
 }
 
+// StartMarginUserDataStream starts cross margin stream and returns Stream
+// with ListenKey.
+func (b *binance) StartMarginUserDataStream() (*Stream, error) {
+	return b.Service.StartMarginUserDataStream()
+}
+
+// StartIsolatedMarginUserDataStream starts isolated margin stream of symbol
+// and returns Stream with ListenKey.
+func (b *binance) StartIsolatedMarginUserDataStream(symbol string) (*Stream, error) {
+	return b.Service.StartIsolatedMarginUserDataStream(symbol)
+}
+
 // KeepAliveUserDataStream prolongs stream livespan.
 func (b *binance) KeepAliveUserDataStream(s *Stream) error {
 	return b.Service.KeepAliveUserDataStream(s)
@@ -653,29 +1530,68 @@ func (b *binance) CloseUserDataStream(s *Stream) error {
 	return b.Service.CloseUserDataStream(s)
 }
 
+// WSEvent holds data common to websocket events.
+//
+// Raw is JSON payload the event was parsed from, which allows reading fields
+// not parsed by the library. It's set only with WithRawEvents option, events
+// not coming from stream messages and events loaded by UnmarshalJSON have it
+// nil.
 type WSEvent struct {
 	Type   string
 	Time   time.Time
 	Symbol string
+	Raw    []byte
 }
 
+// DepthWebsocketRequest represents DepthWebsocket request data.
+//
+// WithSnapshot makes the stream start with OrderBook snapshot of
+// SnapshotLimit levels, see OrderBookRequest, sent as DepthEvent with
+// IsSnapshot set and Type DepthSnapshotEventType. The stream is connected
+// before the snapshot is fetched, so no update is missed; updates already
+// contained in the snapshot are ignored by ApplyDepthDelta.
 type DepthWebsocketRequest struct {
-	Symbol string
+	Symbol        string
+	WithSnapshot  bool
+	SnapshotLimit int
 }
 
 func (b *binance) DepthWebsocket(dwr DepthWebsocketRequest) (chan *DepthEvent, chan struct{}, error) {
 	return b.Service.DepthWebsocket(dwr)
 }
 
+// KlineWebsocketRequest represents KlineWebsocket request data.
+//
+// Intervals subscribe to additional intervals of the same Symbol, in which
+// case all of them are served over one combined stream connection and
+// events can be told apart by their Interval.
 type KlineWebsocketRequest struct {
-	Symbol   string
-	Interval Interval
+	Symbol    string
+	Interval  Interval
+	Intervals []Interval
 }
 
 func (b *binance) KlineWebsocket(kwr KlineWebsocketRequest) (chan *KlineEvent, chan struct{}, error) {
 	return b.Service.KlineWebsocket(kwr)
 }
 
+// KlinesWithLive returns channel emitting last lookback klines of symbol
+// followed by live updates of KlineWebsocket, together with done channel of
+// the stream.
+//
+// Historical klines are emitted as events with Final set for closed klines.
+// Live updates of klines older than the last historical one are dropped,
+// updates of the last one are emitted, so consumers should key klines by
+// OpenTime. Klines closed between loading history and connecting stream are
+// loaded again when the first newer update arrives.
+//
+// Loading them is retried according to retry policy; if it fails anyway,
+// *StreamError of StreamErrorBackfill kind is reported by StreamErrors and
+// the stream is stopped rather than continuing with missing klines.
+func (b *binance) KlinesWithLive(symbol string, interval Interval, lookback int) (chan *KlineEvent, chan struct{}, error) {
+	return b.Service.KlinesWithLive(symbol, interval, lookback)
+}
+
 type AggTradeWebsocketRequest struct {
 	Symbol string
 }
@@ -699,3 +1615,54 @@ type UserDataWebsocketRequest struct {
 func (b *binance) UserDataWebsocket(udwr UserDataWebsocketRequest) (chan *AccountEvent, chan struct{}, error) {
 	return b.Service.UserDataWebsocket(udwr)
 }
+
+// UserDataStream starts user data stream and returns channel of its events
+// together with done channel closed once service context is cancelled.
+//
+// Listen key is kept alive and websocket is reconnected when it fails or
+// listenKeyExpired event is received, with fresh listen key if the previous
+// one expired, so events keep coming from the same channel. The expiration
+// event is passed on as well. Events sent while disconnected are lost, query
+// Account and open orders after reconnection if they matter. Listen key is
+// left to expire when the stream is done.
+func (b *binance) UserDataStream() (chan *AccountEvent, chan struct{}, error) {
+	return b.Service.UserDataStream()
+}
+
+// MarginUserDataStream is UserDataStream of cross margin account.
+func (b *binance) MarginUserDataStream() (chan *AccountEvent, chan struct{}, error) {
+	return b.Service.MarginUserDataStream()
+}
+
+// IsolatedMarginUserDataStream is UserDataStream of isolated margin account
+// of symbol.
+func (b *binance) IsolatedMarginUserDataStream(symbol string) (chan *AccountEvent, chan struct{}, error) {
+	return b.Service.IsolatedMarginUserDataStream(symbol)
+}
+
+func (b *binance) StreamErrors() <-chan error {
+	return b.Service.StreamErrors()
+}
+
+// Streams returns status of every open websocket connection ordered by
+// connection time, including connections of stream and websocket API
+// clients.
+func (b *binance) Streams() []StreamStatus {
+	return b.Service.Streams()
+}
+
+// CloseStream stops stream of done returned by websocket method or
+// UserDataStream, or by Done of StreamClient or WSAPIClient, as if service
+// context was cancelled for that stream only. Done is closed once the stream
+// is torn down. Unknown and already closed streams are ignored.
+func (b *binance) CloseStream(done <-chan struct{}) {
+	b.Service.CloseStream(done)
+}
+
+// Shutdown stops all open streams, including stream and websocket API
+// clients, and waits until their goroutines exit and connections are closed,
+// or until ctx is done, returning its error. REST requests are not affected,
+// cancel service context to stop them too.
+func (b *binance) Shutdown(ctx context.Context) error {
+	return b.Service.Shutdown(ctx)
+}
@@ -0,0 +1,206 @@
+package binance
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAverageFillPrice(t *testing.T) {
+	// many small fills whose naive float summation drifts
+	var fills []*MyTrade
+	for i := 0; i < 1000000; i++ {
+		fills = append(fills, &MyTrade{Price: 2.5, Qty: 0.1, Commission: 0.0001})
+	}
+	tests := []struct {
+		name           string
+		trades         []*MyTrade
+		wantPrice      float64
+		wantQty        float64
+		wantCommission float64
+	}{
+		{name: "empty"},
+		{name: "nil entries", trades: []*MyTrade{nil, nil}},
+		{
+			name:           "single trade",
+			trades:         []*MyTrade{{Price: 4.000001, Qty: 12, Commission: 10.1}},
+			wantPrice:      4.000001,
+			wantQty:        12,
+			wantCommission: 10.1,
+		},
+		{
+			name: "weighted by quantity",
+			trades: []*MyTrade{
+				{Price: 10, Qty: 1, Commission: 0.001},
+				nil,
+				{Price: 20, Qty: 3, Commission: 0.003},
+			},
+			wantPrice:      17.5,
+			wantQty:        4,
+			wantCommission: 0.004,
+		},
+		{
+			name:           "many small fills",
+			trades:         fills,
+			wantPrice:      2.5,
+			wantQty:        100000,
+			wantCommission: 100,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, qty, commission := AverageFillPrice(tt.trades)
+			if price != tt.wantPrice || qty != tt.wantQty || commission != tt.wantCommission {
+				t.Errorf("AverageFillPrice = %v, %v, %v, want %v, %v, %v",
+					price, qty, commission, tt.wantPrice, tt.wantQty, tt.wantCommission)
+			}
+		})
+	}
+}
+
+func TestExecutionReportEventCommission(t *testing.T) {
+	tests := []struct {
+		fixture         string
+		executionType   string
+		commission      float64
+		commissionAsset string
+		executedQty     float64
+	}{
+		{"execution_report_new.json", "NEW", 0, "", 0},
+		{"execution_report_null_commission.json", "NEW", 0, "", 0},
+		{"execution_report_empty_commission.json", "NEW", 0, "", 0},
+		{"execution_report_trade.json", "TRADE", 0.00075, "BNB", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			var e ExecutionReportEvent
+			if err := json.Unmarshal(readTestdata(t, tt.fixture), &e); err != nil {
+				t.Fatal(err)
+			}
+			if e.CurrentExecutionType != tt.executionType {
+				t.Errorf("CurrentExecutionType = %q, want %q", e.CurrentExecutionType, tt.executionType)
+			}
+			if e.CommissionAmount != tt.commission {
+				t.Errorf("CommissionAmount = %v, want %v", e.CommissionAmount, tt.commission)
+			}
+			if e.CommissionAsset != tt.commissionAsset {
+				t.Errorf("CommissionAsset = %q, want %q", e.CommissionAsset, tt.commissionAsset)
+			}
+			if e.CumulativeFilledQuantity != tt.executedQty {
+				t.Errorf("CumulativeFilledQuantity = %v, want %v", e.CumulativeFilledQuantity, tt.executedQty)
+			}
+			if e.OrderId != 4293153 || e.Quantity != 1 || e.Price != 0.1026441 || e.OrderListID != -1 ||
+				e.TransactionTime != 1499405658657 || !(e.IsWorking == (tt.executionType == "NEW")) {
+				t.Errorf("unexpected event %+v", e)
+			}
+			order := e.ToExecutedOrder()
+			if order.ClientOrderID != "mUvoqJxFIILMdfAW5iGSOW" || order.ExecutedQty != tt.executedQty {
+				t.Errorf("unexpected order %+v", order)
+			}
+		})
+	}
+}
+
+func TestHistoryRangePaging(t *testing.T) {
+	tests := []struct {
+		name          string
+		limit         int
+		records       int
+		noIDs         bool
+		wantCalls     int32
+		wantDeposits  int
+		wantLastQuery string
+	}{
+		{"paged", 2, 5, false, 3, 5, "offset=4"},
+		{"single page", 2, 1, false, 1, 1, "limit=2"},
+		{"default page size", 0, 5, false, 1, 5, "limit=1000"},
+		{"records without id", 2, 3, true, 2, 3, "offset=2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			var lastQuery atomic.Value
+			as := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/sapi/v1/capital/deposit/hisrec" {
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+				if atomic.AddInt32(&calls, 1) > 10 {
+					w.WriteHeader(http.StatusBadRequest)
+					fmt.Fprint(w, `{"code":-1,"msg":"too many pages"}`)
+					return
+				}
+				lastQuery.Store(r.URL.RawQuery)
+				offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+				limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+				var records []string
+				for i := offset; i < tt.records && i < offset+limit; i++ {
+					id := strconv.Itoa(i)
+					if tt.noIDs {
+						id = ""
+					}
+					records = append(records, fmt.Sprintf(
+						`{"id":"%s","amount":"1.5","coin":"BTC","insertTime":1599621997000,"status":1}`, id))
+				}
+				fmt.Fprintf(w, "[%s]", strings.Join(records, ","))
+			})
+			b := NewBinance(as)
+			end := time.Date(2020, 9, 10, 0, 0, 0, 0, time.UTC)
+			deposits, err := b.DepositHistoryRange(HistoryRequest{
+				StartTime: end.Add(-10 * 24 * time.Hour),
+				EndTime:   end,
+				Limit:     tt.limit,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if calls := atomic.LoadInt32(&calls); calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if len(deposits) != tt.wantDeposits {
+				t.Errorf("deposits = %d, want %d", len(deposits), tt.wantDeposits)
+			}
+			if q := lastQuery.Load().(string); !strings.Contains(q, tt.wantLastQuery) {
+				t.Errorf("last query %q doesn't contain %q", q, tt.wantLastQuery)
+			}
+		})
+	}
+}
+
+func TestWithdrawHistoryRangeIgnoredOffset(t *testing.T) {
+	var calls int32
+	as := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sapi/v1/capital/withdraw/history" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if atomic.AddInt32(&calls, 1) > 10 {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"code":-1,"msg":"too many pages"}`)
+			return
+		}
+		fmt.Fprint(w, `[
+			{"id":"a","amount":"1","transactionFee":"0.1","coin":"USDT","applyTime":"2020-09-01 10:00:00","status":6},
+			{"id":"b","amount":"2","transactionFee":"0.1","coin":"USDT","applyTime":"2020-09-02 10:00:00","status":6}
+		]`)
+	})
+	b := NewBinance(as)
+	end := time.Date(2020, 9, 10, 0, 0, 0, 0, time.UTC)
+	withdrawals, err := b.WithdrawHistoryRange(HistoryRequest{
+		StartTime: end.Add(-10 * 24 * time.Hour),
+		EndTime:   end,
+		Limit:     2,
+	})
+	if err == nil || !strings.Contains(err.Error(), "offset is ignored") {
+		t.Fatalf("err = %v, want ignored offset error", err)
+	}
+	if withdrawals != nil {
+		t.Errorf("withdrawals = %v, want nil", withdrawals)
+	}
+	if calls := atomic.LoadInt32(&calls); calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
@@ -0,0 +1,116 @@
+package binance
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// CircuitState represents state of circuit breaker enabled by
+// WithCircuitBreaker.
+type CircuitState string
+
+var (
+	// CircuitClosed lets requests through.
+	CircuitClosed = CircuitState("CLOSED")
+	// CircuitOpen fails requests with ErrCircuitOpen until cooldown elapses.
+	CircuitOpen = CircuitState("OPEN")
+	// CircuitHalfOpen lets single probing request through, which closes the
+	// circuit if it succeeds and opens it again otherwise.
+	CircuitHalfOpen = CircuitState("HALF_OPEN")
+)
+
+// ErrCircuitOpen is returned for REST requests not sent because circuit
+// breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// circuitBreaker opens after threshold consecutive failures or IP ban and
+// stays open for cooldown, or longer if Binance asks to retry later.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+func (cb *circuitBreaker) allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	switch cb.stateLocked() {
+	case CircuitOpen:
+		return ErrCircuitOpen
+	case CircuitHalfOpen:
+		if cb.probing {
+			return ErrCircuitOpen
+		}
+		cb.probing = true
+	}
+	return nil
+}
+
+// record updates breaker with result of request, resp is nil if request
+// failed with network error. Canceled requests are neither failures nor
+// successes. It reports whether circuit was opened.
+func (cb *circuitBreaker) record(resp *http.Response, err error, canceled bool) bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	probing := cb.probing
+	cb.probing = false
+	if canceled {
+		return false
+	}
+
+	ban := resp != nil && resp.StatusCode == 418
+	failed := err != nil || resp.StatusCode >= 500 || resp.StatusCode == 429 || ban
+	if !failed {
+		cb.failures = 0
+		cb.openUntil = time.Time{}
+		return false
+	}
+	cb.failures++
+	if !ban && !probing && cb.failures < cb.threshold {
+		return false
+	}
+	cooldown := cb.cooldown
+	if retryAfter := retryAfter(resp); retryAfter > cooldown {
+		cooldown = retryAfter
+	}
+	cb.openUntil = time.Now().Add(cooldown)
+	return true
+}
+
+func (cb *circuitBreaker) state() CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.stateLocked()
+}
+
+func (cb *circuitBreaker) stateLocked() CircuitState {
+	switch {
+	case cb.openUntil.IsZero():
+		return CircuitClosed
+	case time.Now().Before(cb.openUntil):
+		return CircuitOpen
+	default:
+		return CircuitHalfOpen
+	}
+}
+
+// retryAfter returns delay of Retry-After header sent with 418 and 429
+// responses, zero if there's none.
+func retryAfter(resp *http.Response) time.Duration {
+	if resp == nil {
+		return 0
+	}
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,39 @@
+package binance
+
+import (
+	"regexp"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// clientOrderIDPattern is format of client order ids accepted by Binance.
+var clientOrderIDPattern = regexp.MustCompile(`^[.A-Z:/a-z0-9_-]{1,36}$`)
+
+// ValidateClientOrderID checks that id satisfies Binance length and charset
+// constraints of client order ids.
+func ValidateClientOrderID(id string) error {
+	if !clientOrderIDPattern.MatchString(id) {
+		return errors.Errorf("invalid client order id %q, must match %s", id, clientOrderIDPattern)
+	}
+	return nil
+}
+
+// clientOrderID returns client order id to place order with. If id is empty
+// and generation is enabled by WithClientOrderIDs, new id is generated.
+func (as *apiService) clientOrderID(id string) (string, error) {
+	if id == "" {
+		if !as.clientOrderIDs {
+			return "", nil
+		}
+		seq := atomic.AddUint64(as.clientOrderIDSeq, 1)
+		id = as.clientOrderIDPrefix +
+			strconv.FormatInt(unixMillis(time.Now()), 36) + "-" + strconv.FormatUint(seq, 36)
+	}
+	if err := ValidateClientOrderID(id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
@@ -0,0 +1,191 @@
+//go:build decimal
+// +build decimal
+
+package binance
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// Types of this file hold money fields as decimal.Decimal parsed from values
+// sent by API, avoiding float64 entirely. They're built with "decimal" build
+// tag only, so that default build doesn't depend on decimal package.
+
+// DecimalOrder is Order with decimal price and quantity.
+type DecimalOrder struct {
+	Price    decimal.Decimal
+	Quantity decimal.Decimal
+}
+
+// Decimal returns o with decimal price and quantity.
+func (o *Order) Decimal() (*DecimalOrder, error) {
+	price, err := decimalFromRaw(o.RawPrice, o.Price)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse Order.Price")
+	}
+	quantity, err := decimalFromRaw(o.RawQuantity, o.Quantity)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse Order.Quantity")
+	}
+	return &DecimalOrder{
+		Price:    price,
+		Quantity: quantity,
+	}, nil
+}
+
+// DecimalAggTrade is AggTrade with decimal price and quantity.
+type DecimalAggTrade struct {
+	ID             int
+	Price          decimal.Decimal
+	Quantity       decimal.Decimal
+	FirstTradeID   int
+	LastTradeID    int
+	Timestamp      time.Time
+	BuyerMaker     bool
+	BestPriceMatch bool
+}
+
+// Decimal returns at with decimal price and quantity.
+func (at *AggTrade) Decimal() (*DecimalAggTrade, error) {
+	price, err := decimalFromRaw(at.RawPrice, at.Price)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse AggTrade.Price")
+	}
+	quantity, err := decimalFromRaw(at.RawQuantity, at.Quantity)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse AggTrade.Quantity")
+	}
+	return &DecimalAggTrade{
+		ID:             at.ID,
+		Price:          price,
+		Quantity:       quantity,
+		FirstTradeID:   at.FirstTradeID,
+		LastTradeID:    at.LastTradeID,
+		Timestamp:      at.Timestamp,
+		BuyerMaker:     at.BuyerMaker,
+		BestPriceMatch: at.BestPriceMatch,
+	}, nil
+}
+
+// DecimalKline is Kline with decimal prices and volumes.
+type DecimalKline struct {
+	OpenTime                 time.Time
+	Open                     decimal.Decimal
+	High                     decimal.Decimal
+	Low                      decimal.Decimal
+	Close                    decimal.Decimal
+	Volume                   decimal.Decimal
+	CloseTime                time.Time
+	QuoteAssetVolume         decimal.Decimal
+	NumberOfTrades           int
+	TakerBuyBaseAssetVolume  decimal.Decimal
+	TakerBuyQuoteAssetVolume decimal.Decimal
+}
+
+// Decimal returns k with decimal prices and volumes.
+func (k *Kline) Decimal() (*DecimalKline, error) {
+	dk := &DecimalKline{
+		OpenTime:       k.OpenTime,
+		CloseTime:      k.CloseTime,
+		NumberOfTrades: k.NumberOfTrades,
+	}
+	fields := []struct {
+		name  string
+		raw   string
+		value float64
+		dst   *decimal.Decimal
+	}{
+		{"Open", k.RawOpen, k.Open, &dk.Open},
+		{"High", k.RawHigh, k.High, &dk.High},
+		{"Low", k.RawLow, k.Low, &dk.Low},
+		{"Close", k.RawClose, k.Close, &dk.Close},
+		{"Volume", k.RawVolume, k.Volume, &dk.Volume},
+		{"QuoteAssetVolume", k.RawQuoteAssetVolume, k.QuoteAssetVolume, &dk.QuoteAssetVolume},
+		{"TakerBuyBaseAssetVolume", k.RawTakerBuyBaseAssetVolume, k.TakerBuyBaseAssetVolume, &dk.TakerBuyBaseAssetVolume},
+		{"TakerBuyQuoteAssetVolume", k.RawTakerBuyQuoteAssetVolume, k.TakerBuyQuoteAssetVolume, &dk.TakerBuyQuoteAssetVolume},
+	}
+	for _, f := range fields {
+		d, err := decimalFromRaw(f.raw, f.value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot parse Kline.%s", f.name)
+		}
+		*f.dst = d
+	}
+	return dk, nil
+}
+
+// DecimalBalance is Balance with decimal amounts.
+type DecimalBalance struct {
+	Asset  string
+	Free   decimal.Decimal
+	Locked decimal.Decimal
+}
+
+// Decimal returns b with decimal amounts.
+func (b *Balance) Decimal() (*DecimalBalance, error) {
+	free, err := decimalFromRaw(b.RawFree, b.Free)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse Balance.Free")
+	}
+	locked, err := decimalFromRaw(b.RawLocked, b.Locked)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse Balance.Locked")
+	}
+	return &DecimalBalance{
+		Asset:  b.Asset,
+		Free:   free,
+		Locked: locked,
+	}, nil
+}
+
+// DecimalMyTrade is MyTrade with decimal price, quantity and commission.
+type DecimalMyTrade struct {
+	ID              int64
+	Price           decimal.Decimal
+	Qty             decimal.Decimal
+	Commission      decimal.Decimal
+	CommissionAsset string
+	Time            time.Time
+	IsBuyer         bool
+	IsMaker         bool
+	IsBestMatch     bool
+}
+
+// Decimal returns t with decimal price, quantity and commission.
+func (t *MyTrade) Decimal() (*DecimalMyTrade, error) {
+	price, err := decimalFromRaw(t.RawPrice, t.Price)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse MyTrade.Price")
+	}
+	qty, err := decimalFromRaw(t.RawQty, t.Qty)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse MyTrade.Qty")
+	}
+	commission, err := decimalFromRaw(t.RawCommission, t.Commission)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse MyTrade.Commission")
+	}
+	return &DecimalMyTrade{
+		ID:              t.ID,
+		Price:           price,
+		Qty:             qty,
+		Commission:      commission,
+		CommissionAsset: t.CommissionAsset,
+		Time:            t.Time,
+		IsBuyer:         t.IsBuyer,
+		IsMaker:         t.IsMaker,
+		IsBestMatch:     t.IsBestMatch,
+	}, nil
+}
+
+// decimalFromRaw parses raw value sent by API, falling back to value when
+// raw one isn't available.
+func decimalFromRaw(raw string, value float64) (decimal.Decimal, error) {
+	if raw == "" {
+		return decimal.NewFromFloat(value), nil
+	}
+	return decimal.NewFromString(raw)
+}
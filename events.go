@@ -0,0 +1,121 @@
+package binance
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// WebsocketEvent is implemented by every event delivered by websocket streams
+// through embedded WSEvent.
+//
+// Use type switch on the concrete type (e.g. *DepthEvent, *KlineEvent) to
+// access event specific data.
+type WebsocketEvent interface {
+	// EventType returns type of event as reported by Binance, e.g. "depthUpdate".
+	EventType() string
+	// EventTime returns time when event was emitted.
+	EventTime() time.Time
+	// EventSymbol returns symbol the event relates to.
+	EventSymbol() string
+}
+
+// EventType returns type of event as reported by Binance.
+func (e WSEvent) EventType() string {
+	return e.Type
+}
+
+// EventTime returns time when event was emitted.
+func (e WSEvent) EventTime() time.Time {
+	return e.Time
+}
+
+// EventSymbol returns symbol the event relates to.
+func (e WSEvent) EventSymbol() string {
+	return e.Symbol
+}
+
+// StreamErrorKind describes why websocket stream stopped.
+type StreamErrorKind string
+
+var (
+	StreamErrorRead  = StreamErrorKind("read")
+	StreamErrorParse = StreamErrorKind("parse")
+	// StreamErrorBackfill is reported when data missed by stream couldn't
+	// be loaded by REST, e.g. by KlinesWithLive.
+	StreamErrorBackfill = StreamErrorKind("backfill")
+)
+
+// StreamError is reported through Binance.StreamErrors when stream stops
+// because of failure. Streams stopped by context cancellation report nothing.
+type StreamError struct {
+	Kind StreamErrorKind
+	// Stream is URL of the stream.
+	Stream string
+	// Done is done channel returned when the stream was opened.
+	Done chan struct{}
+	Err  error
+}
+
+// Error returns formatted error message.
+func (e *StreamError) Error() string {
+	return fmt.Sprintf("stream %s %s failed: %s", e.Stream, e.Kind, e.Err)
+}
+
+// Cause returns underlying error.
+func (e *StreamError) Cause() error {
+	return e.Err
+}
+
+var websocketEventType = reflect.TypeOf((*WebsocketEvent)(nil)).Elem()
+
+// MergeWebsocketEvents forwards events from several typed stream channels
+// (e.g. chan *DepthEvent and chan *TradeEvent) into a single channel, so that
+// heterogeneous events can be consumed from one select loop.
+//
+// Forwarding stops and returned channel is closed when done is closed or all
+// source channels are closed. Error is returned if any of chans isn't
+// a receivable channel of WebsocketEvent implementation.
+func MergeWebsocketEvents(done <-chan struct{}, chans ...interface{}) (chan WebsocketEvent, error) {
+	cases := make([]reflect.SelectCase, 0, len(chans)+1)
+	cases = append(cases, reflect.SelectCase{
+		Dir:  reflect.SelectRecv,
+		Chan: reflect.ValueOf(done),
+	})
+	for i, ch := range chans {
+		v := reflect.ValueOf(ch)
+		if v.Kind() != reflect.Chan || v.Type().ChanDir()&reflect.RecvDir == 0 {
+			return nil, errors.Errorf("argument %d is not a receivable channel: %T", i, ch)
+		}
+		if !v.Type().Elem().Implements(websocketEventType) {
+			return nil, errors.Errorf("argument %d is not a channel of WebsocketEvent: %T", i, ch)
+		}
+		cases = append(cases, reflect.SelectCase{
+			Dir:  reflect.SelectRecv,
+			Chan: v,
+		})
+	}
+
+	wech := make(chan WebsocketEvent)
+	go func() {
+		defer close(wech)
+		for len(cases) > 1 {
+			chosen, value, ok := reflect.Select(cases)
+			if chosen == 0 {
+				return
+			}
+			if !ok {
+				cases = append(cases[:chosen], cases[chosen+1:]...)
+				continue
+			}
+			select {
+			case wech <- value.Interface().(WebsocketEvent):
+			case <-done:
+				return
+			}
+		}
+	}()
+	return wech, nil
+}
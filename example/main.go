@@ -47,7 +47,7 @@ func main() {
 			case ke := <-kech:
 				fmt.Printf("%#v\n", ke)
 			case <-done:
-				break
+				return
 			}
 		}
 	}()
@@ -59,8 +59,9 @@ func main() {
 	fmt.Println("waiting for signal")
 	<-done
 	fmt.Println("exit")
-	return
+}
 
+func restExamples(b binance.Binance) {
 	kl, err := b.Klines(binance.KlinesRequest{
 		Symbol:   "BNBETH",
 		Interval: binance.Hour,
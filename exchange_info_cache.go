@@ -0,0 +1,113 @@
+package binance
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultExchangeInfoTTL is time cached exchange info is used for unless
+// WithExchangeInfoTTL is provided.
+const DefaultExchangeInfoTTL = 10 * time.Minute
+
+// exchangeInfoCache holds exchange info for helpers, so they don't repeat the
+// heavy request. Lock is held while fetching, so concurrent callers wait for
+// single request instead of sending their own.
+type exchangeInfoCache struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	info      *ExchangeInfo
+	fetchedAt time.Time
+}
+
+func (as *apiService) CachedExchangeInfo() (*ExchangeInfo, error) {
+	c := as.exchangeInfo
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.info != nil && (c.ttl <= 0 || time.Since(c.fetchedAt) < c.ttl) {
+		return c.info, nil
+	}
+	return as.refreshExchangeInfoLocked()
+}
+
+func (as *apiService) RefreshExchangeInfo() (*ExchangeInfo, error) {
+	as.exchangeInfo.mu.Lock()
+	defer as.exchangeInfo.mu.Unlock()
+	return as.refreshExchangeInfoLocked()
+}
+
+// refreshExchangeInfoLocked fetches exchange info into cache, which has to be
+// locked. Cached info is kept if fetching fails.
+func (as *apiService) refreshExchangeInfoLocked() (*ExchangeInfo, error) {
+	info, err := as.ExchangeInfo()
+	if err != nil {
+		return nil, err
+	}
+	as.exchangeInfo.info = info
+	as.exchangeInfo.fetchedAt = time.Now()
+	return info, nil
+}
+
+// SymbolStatus returns trading status of symbol from cached exchange info,
+// e.g. TRADING, HALT or BREAK.
+func (b *binance) SymbolStatus(symbol string) (string, error) {
+	info, err := b.Service.CachedExchangeInfo()
+	if err != nil {
+		return "", err
+	}
+	symbol = NormalizeSymbol(symbol)
+	for _, s := range info.Symbols {
+		if s.Asset == symbol {
+			return s.Status, nil
+		}
+	}
+	return "", errors.Errorf("symbol %s not found in exchange info", symbol)
+}
+
+// symbolStatusTrading is status of symbol open for trading.
+const symbolStatusTrading = "TRADING"
+
+// SymbolsByQuoteAsset returns symbols quoted in quote asset, e.g. USDT, which
+// are open for trading, from cached exchange info.
+func (b *binance) SymbolsByQuoteAsset(quote string) ([]string, error) {
+	info, err := b.Service.CachedExchangeInfo()
+	if err != nil {
+		return nil, err
+	}
+	quote = strings.ToUpper(quote)
+	var symbols []string
+	for _, s := range info.Symbols {
+		if s.QuoteAsset == quote && s.Status == symbolStatusTrading {
+			symbols = append(symbols, s.Asset)
+		}
+	}
+	return symbols, nil
+}
+
+// RateLimits returns rate limits reported by cached exchange info, only those
+// of types if any is given, so that budgets follow limits set by server.
+func (b *binance) RateLimits(types ...RateLimitType) ([]RateLimit, error) {
+	info, err := b.Service.CachedExchangeInfo()
+	if err != nil {
+		return nil, err
+	}
+	var limits []RateLimit
+	for _, rl := range info.RateLimits {
+		if len(types) == 0 || hasRateLimitType(types, rl.RateLimitType) {
+			limits = append(limits, rl)
+		}
+	}
+	return limits, nil
+}
+
+func hasRateLimitType(types []RateLimitType, t RateLimitType) bool {
+	for _, typ := range types {
+		if typ == t {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package binance
+
+import "time"
+
+// PositionSide represents futures positionSide enum.
+type PositionSide string
+
+var (
+	PositionSideBoth  = PositionSide("BOTH")
+	PositionSideLong  = PositionSide("LONG")
+	PositionSideShort = PositionSide("SHORT")
+)
+
+// FuturesExchangeInfo returns USDT-M futures trading rules and symbols.
+func (b *binance) FuturesExchangeInfo() (*ExchangeInfo, error) {
+	return b.Service.FuturesExchangeInfo()
+}
+
+// FuturesKlines returns USDT-M futures klines/candlestick data.
+func (b *binance) FuturesKlines(kr KlinesRequest) ([]*Kline, error) {
+	return b.Service.FuturesKlines(kr)
+}
+
+// FuturesNewOrderRequest represents FuturesNewOrder request data.
+type FuturesNewOrderRequest struct {
+	Symbol           string
+	Side             OrderSide
+	PositionSide     PositionSide
+	Type             OrderType
+	TimeInForce      TimeInForce
+	Quantity         float64
+	Price            float64
+	StopPrice        float64
+	ReduceOnly       bool
+	NewClientOrderID string
+	RecvWindow       time.Duration
+	Timestamp        time.Time
+}
+
+// FuturesOrder represents data about USDT-M futures order.
+type FuturesOrder struct {
+	Symbol        string
+	OrderID       int64
+	ClientOrderID string
+	Price         float64
+	AvgPrice      float64
+	OrigQty       float64
+	ExecutedQty   float64
+	CumQuote      float64
+	Status        OrderStatus
+	TimeInForce   TimeInForce
+	Type          OrderType
+	Side          OrderSide
+	PositionSide  PositionSide
+	StopPrice     float64
+	ReduceOnly    bool
+	UpdateTime    time.Time
+}
+
+// FuturesNewOrder places new USDT-M futures order.
+func (b *binance) FuturesNewOrder(fnor FuturesNewOrderRequest) (*FuturesOrder, error) {
+	return b.Service.FuturesNewOrder(fnor)
+}
+
+// FuturesAccount represents USDT-M futures account information.
+type FuturesAccount struct {
+	TotalWalletBalance    float64
+	TotalUnrealizedProfit float64
+	TotalMarginBalance    float64
+	AvailableBalance      float64
+	MaxWithdrawAmount     float64
+	CanTrade              bool
+	CanDeposit            bool
+	CanWithdraw           bool
+	UpdateTime            time.Time
+	Assets                []*FuturesAsset
+}
+
+// FuturesAsset represents single asset of USDT-M futures account.
+type FuturesAsset struct {
+	Asset            string  `json:"asset"`
+	WalletBalance    float64 `json:"walletBalance,string"`
+	UnrealizedProfit float64 `json:"unrealizedProfit,string"`
+	MarginBalance    float64 `json:"marginBalance,string"`
+	AvailableBalance float64 `json:"availableBalance,string"`
+}
+
+// FuturesAccount returns USDT-M futures account data.
+func (b *binance) FuturesAccount(ar AccountRequest) (*FuturesAccount, error) {
+	return b.Service.FuturesAccount(ar)
+}
+
+// FuturesPositionRiskRequest represents FuturesPositionRisk request data.
+type FuturesPositionRiskRequest struct {
+	Symbol     string
+	RecvWindow time.Duration
+	Timestamp  time.Time
+}
+
+// FuturesPosition represents USDT-M futures position information.
+type FuturesPosition struct {
+	Symbol           string
+	PositionAmt      float64
+	EntryPrice       float64
+	MarkPrice        float64
+	UnrealizedProfit float64
+	LiquidationPrice float64
+	Leverage         int
+	MarginType       string
+	PositionSide     PositionSide
+	UpdateTime       time.Time
+}
+
+// FuturesPositionRisk returns current USDT-M futures positions.
+func (b *binance) FuturesPositionRisk(fprr FuturesPositionRiskRequest) ([]*FuturesPosition, error) {
+	return b.Service.FuturesPositionRisk(fprr)
+}
+
+// FundingRateRequest represents FundingRateHistory request data.
+type FundingRateRequest struct {
+	Symbol    string
+	StartTime int64
+	EndTime   int64
+	Limit     int
+}
+
+// FundingRate represents single funding rate record.
+type FundingRate struct {
+	Symbol      string
+	FundingRate float64
+	FundingTime time.Time
+	MarkPrice   float64
+}
+
+// FundingRateHistory returns historical USDT-M futures funding rates.
+func (b *binance) FundingRateHistory(frr FundingRateRequest) ([]*FundingRate, error) {
+	return b.Service.FundingRateHistory(frr)
+}
+
+// MarkPriceEvent represents mark price and funding rate update.
+type MarkPriceEvent struct {
+	WSEvent
+	MarkPrice            float64
+	IndexPrice           float64
+	EstimatedSettlePrice float64
+	FundingRate          float64
+	NextFundingTime      time.Time
+}
+
+type MarkPriceWebsocketRequest struct {
+	Symbol string
+}
+
+func (b *binance) MarkPriceWebsocket(mpwr MarkPriceWebsocketRequest) (chan *MarkPriceEvent, chan struct{}, error) {
+	return b.Service.MarkPriceWebsocket(mpwr)
+}
@@ -0,0 +1,265 @@
+package binance
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+)
+
+const (
+	testAPIKey    = "test-api-key"
+	testAPISecret = "test-api-secret"
+)
+
+// newTestService returns service sending REST requests to test server
+// serving them by handler.
+func newTestService(t *testing.T, handler http.HandlerFunc, opts ...ServiceOption) *apiService {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return newTestServiceOf(t, srv.URL, opts...)
+}
+
+// newTestStreamService returns service sending both REST requests and
+// websocket connections to test TLS server serving them by handler, so that
+// streams with hardcoded URLs can be tested. Handler tells websocket
+// connections apart by their path, e.g. /ws/bnbbtc@depth.
+func newTestStreamService(t *testing.T, handler http.HandlerFunc, opts ...ServiceOption) *apiService {
+	t.Helper()
+	srv := httptest.NewTLSServer(handler)
+	t.Cleanup(srv.Close)
+	addr := srv.Listener.Addr().String()
+	dialer := &websocket.Dialer{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		NetDialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	}
+	opts = append([]ServiceOption{WithDialer(dialer), WithHTTPClient(srv.Client())}, opts...)
+	return newTestServiceOf(t, srv.URL, opts...)
+}
+
+// newTestServiceOf returns service sending both spot and futures REST
+// requests to url.
+func newTestServiceOf(t *testing.T, url string, opts ...ServiceOption) *apiService {
+	opts = append([]ServiceOption{WithFuturesURL(url)}, opts...)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	signer := &HmacSigner{Key: []byte(testAPISecret)}
+	return NewAPIService(url, testAPIKey, signer, nil, ctx, opts...).(*apiService)
+}
+
+// upgradeTestWS upgrades request to websocket connection closed with test.
+func upgradeTestWS(t *testing.T, w http.ResponseWriter, r *http.Request) *websocket.Conn {
+	t.Helper()
+	c, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+	if err != nil {
+		t.Errorf("websocket upgrade failed: %v", err)
+		return nil
+	}
+	return c
+}
+
+// holdTestWS keeps connection c open until client closes it.
+func holdTestWS(c *websocket.Conn) {
+	defer c.Close()
+	for {
+		if _, _, err := c.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// readTestdata returns content of testdata file recorded from Binance API.
+func readTestdata(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// restFixture tests parsing of REST response recorded in testdata.
+type restFixture struct {
+	name    string
+	method  string
+	path    string
+	fixture string
+	// status of response, 200 if zero
+	status int
+	call   func(as *apiService) (interface{}, error)
+	// want is compared with result field by field, check is used instead
+	// if set
+	want  interface{}
+	check func(t *testing.T, got interface{})
+	// wantErr is API error expected instead of result
+	wantErr *Error
+}
+
+// testRESTFixtures serves fixture of every test to its call and checks the
+// parsed result.
+func testRESTFixtures(t *testing.T, tests []restFixture) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := readTestdata(t, tt.fixture)
+			as := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != tt.method || r.URL.Path != tt.path {
+					t.Errorf("unexpected request %s %s, want %s %s", r.Method, r.URL.Path, tt.method, tt.path)
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+				}
+				w.Write(body)
+			})
+			got, err := tt.call(as)
+			if tt.wantErr != nil {
+				if apiErr, ok := errors.Cause(err).(*Error); !ok || *apiErr != *tt.wantErr {
+					t.Errorf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.check != nil {
+				tt.check(t, got)
+				return
+			}
+			checkFields(t, got, tt.want)
+		})
+	}
+}
+
+// streamFixture tests parsing of websocket stream message recorded in
+// testdata.
+type streamFixture struct {
+	name    string
+	path    string
+	fixture string
+	// start starts stream and returns its event channel
+	start func(as *apiService) (interface{}, error)
+	want  interface{}
+}
+
+// testStreamFixtures sends fixture of every test by its stream and checks
+// the first event received.
+func testStreamFixtures(t *testing.T, tests []streamFixture) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := readTestdata(t, tt.fixture)
+			as := newTestStreamService(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tt.path {
+					t.Errorf("unexpected request %s, want %s", r.URL.Path, tt.path)
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				c := upgradeTestWS(t, w, r)
+				if c == nil {
+					return
+				}
+				c.WriteMessage(websocket.TextMessage, message)
+				holdTestWS(c)
+			})
+			events, err := tt.start(as)
+			if err != nil {
+				t.Fatal(err)
+			}
+			chosen, event, _ := reflect.Select([]reflect.SelectCase{
+				{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(events)},
+				{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(time.After(5 * time.Second))},
+			})
+			if chosen != 0 {
+				t.Fatal("event not received")
+			}
+			checkFields(t, event.Interface(), tt.want)
+		})
+	}
+}
+
+// checkFields compares got with want field by field, reporting every field
+// that differs by its path, e.g. Bids[0].Price. Times are equal if they are
+// the same instant, parsed times are required to be in UTC.
+func checkFields(t *testing.T, got, want interface{}) {
+	t.Helper()
+	for _, diff := range fieldDiffs("", reflect.ValueOf(got), reflect.ValueOf(want)) {
+		t.Error(diff)
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func fieldDiffs(path string, got, want reflect.Value) []string {
+	if !got.IsValid() || !want.IsValid() {
+		if got.IsValid() != want.IsValid() {
+			return []string{fmt.Sprintf("%s: got %v, want %v", path, got, want)}
+		}
+		return nil
+	}
+	if got.Type() != want.Type() {
+		return []string{fmt.Sprintf("%s: got %s, want %s", path, got.Type(), want.Type())}
+	}
+	switch want.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if got.IsNil() || want.IsNil() {
+			if got.IsNil() != want.IsNil() {
+				return []string{fmt.Sprintf("%s: got %v, want %v", path, got, want)}
+			}
+			return nil
+		}
+		return fieldDiffs(path, got.Elem(), want.Elem())
+	case reflect.Struct:
+		if want.Type() == timeType {
+			g, w := got.Interface().(time.Time), want.Interface().(time.Time)
+			if !g.Equal(w) {
+				return []string{fmt.Sprintf("%s: got %v, want %v", path, g, w)}
+			}
+			if !g.IsZero() && g.Location() != time.UTC {
+				return []string{fmt.Sprintf("%s: got time in %v, want UTC", path, g.Location())}
+			}
+			return nil
+		}
+		var diffs []string
+		for i := 0; i < want.NumField(); i++ {
+			field := want.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			name := field.Name
+			if path != "" {
+				name = path + "." + name
+			}
+			diffs = append(diffs, fieldDiffs(name, got.Field(i), want.Field(i))...)
+		}
+		return diffs
+	case reflect.Slice, reflect.Array:
+		if got.Len() != want.Len() {
+			return []string{fmt.Sprintf("%s: got %d elements, want %d", path, got.Len(), want.Len())}
+		}
+		var diffs []string
+		for i := 0; i < want.Len(); i++ {
+			diffs = append(diffs, fieldDiffs(fmt.Sprintf("%s[%d]", path, i), got.Index(i), want.Index(i))...)
+		}
+		return diffs
+	default:
+		if !reflect.DeepEqual(got.Interface(), want.Interface()) {
+			return []string{fmt.Sprintf("%s: got %#v, want %#v", path, got.Interface(), want.Interface())}
+		}
+		return nil
+	}
+}
@@ -1,5 +1,12 @@
 package binance
 
+import (
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
 // Interval represents interval enum.
 type Interval string
 
@@ -21,6 +28,32 @@ var (
 	Month          = Interval("1M")
 )
 
+var intervalUnits = map[byte]time.Duration{
+	's': time.Second,
+	'm': time.Minute,
+	'h': time.Hour,
+	'd': 24 * time.Hour,
+	'w': 7 * 24 * time.Hour,
+}
+
+// Duration returns length of interval. Error is returned for month-based
+// intervals, e.g. Month, which have no fixed length.
+func (i Interval) Duration() (time.Duration, error) {
+	s := string(i)
+	if len(s) < 2 {
+		return 0, errors.Errorf("invalid interval %q", s)
+	}
+	unit, ok := intervalUnits[s[len(s)-1]]
+	if !ok {
+		return 0, errors.Errorf("interval %q has no fixed duration", s)
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n <= 0 {
+		return 0, errors.Errorf("invalid interval %q", s)
+	}
+	return time.Duration(n) * unit, nil
+}
+
 // TimeInForce represents timeInForce enum.
 type TimeInForce string
 
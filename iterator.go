@@ -0,0 +1,131 @@
+package binance
+
+// iteratorPageLimit is number of records requested by every page.
+const iteratorPageLimit = 1000
+
+// AggTradesIterator iterates aggregate trades requesting them page by page,
+// so only one page is held in memory at a time.
+//
+// Iteration starts at FromID or StartTime of the request, the oldest trades
+// available otherwise, and ends with the latest trade or once EndTime is
+// passed. Limit sets page size, 1000 by default.
+type AggTradesIterator struct {
+	service Service
+	req     AggTradesRequest
+	endTime int64
+	page    []*AggTrade
+	pos     int
+	last    bool
+	err     error
+}
+
+// IterateAggTrades returns iterator of aggregate trades matching atr.
+func (b *binance) IterateAggTrades(atr AggTradesRequest) *AggTradesIterator {
+	if atr.Limit == 0 {
+		atr.Limit = iteratorPageLimit
+	}
+	if atr.FromID == 0 && atr.StartTime == 0 {
+		// oldest trade has id 0, which wouldn't be sent as fromId
+		atr.StartTime = 1
+	}
+	endTime := atr.EndTime
+	// pages are requested by id, which can't be combined with time range
+	atr.EndTime = 0
+	return &AggTradesIterator{
+		service: b.Service,
+		req:     atr,
+		endTime: endTime,
+	}
+}
+
+// Next returns the next trade, false is returned once iteration ends or
+// fails, see Err.
+func (it *AggTradesIterator) Next() (*AggTrade, bool) {
+	if it.pos == len(it.page) {
+		if it.last || it.err != nil {
+			return nil, false
+		}
+		page, err := it.service.AggTrades(it.req)
+		if err != nil {
+			it.err = err
+			return nil, false
+		}
+		it.page, it.pos = page, 0
+		it.last = len(page) < it.req.Limit
+		if len(page) == 0 {
+			return nil, false
+		}
+		it.req.FromID = int64(page[len(page)-1].ID) + 1
+		it.req.StartTime = 0
+	}
+	at := it.page[it.pos]
+	if it.endTime != 0 && unixMillis(at.Timestamp) > it.endTime {
+		it.page, it.pos, it.last = nil, 0, true
+		return nil, false
+	}
+	it.pos++
+	return at, true
+}
+
+// Err returns error which ended iteration, if any.
+func (it *AggTradesIterator) Err() error {
+	return it.err
+}
+
+// KlinesIterator iterates klines requesting them page by page, so only one
+// page is held in memory at a time.
+//
+// Iteration starts at StartTime of the request, the oldest kline otherwise,
+// and ends with the latest kline or the last one opened before EndTime.
+// Limit sets page size, 1000 by default.
+type KlinesIterator struct {
+	service Service
+	req     KlinesRequest
+	page    []*Kline
+	pos     int
+	last    bool
+	err     error
+}
+
+// IterateKlines returns iterator of klines matching kr.
+func (b *binance) IterateKlines(kr KlinesRequest) *KlinesIterator {
+	if kr.Limit == 0 {
+		kr.Limit = iteratorPageLimit
+	}
+	if kr.StartTime == 0 {
+		kr.StartTime = 1
+	}
+	return &KlinesIterator{
+		service: b.Service,
+		req:     kr,
+	}
+}
+
+// Next returns the next kline, false is returned once iteration ends or
+// fails, see Err.
+func (it *KlinesIterator) Next() (*Kline, bool) {
+	if it.pos == len(it.page) {
+		if it.last || it.err != nil {
+			return nil, false
+		}
+		page, err := it.service.Klines(it.req)
+		if err != nil {
+			it.err = err
+			return nil, false
+		}
+		it.page, it.pos = page, 0
+		it.last = len(page) < it.req.Limit
+		if len(page) == 0 {
+			return nil, false
+		}
+		it.req.StartTime = unixMillis(page[len(page)-1].OpenTime) + 1
+	}
+	k := it.page[it.pos]
+	it.pos++
+	return k, true
+}
+
+// Err returns error which ended iteration, if any.
+func (it *KlinesIterator) Err() error {
+	return it.err
+}
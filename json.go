@@ -0,0 +1,480 @@
+package binance
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Market data, orders and every websocket event are marshaled to JSON with
+// times encoded as Binance millisecond timestamps rather than RFC 3339
+// strings, so they can be stored and loaded back without losing the original
+// representation. ExecutionReportEvent keeps times as millisecond fields
+// already. Request types and account history, wallet, futures and
+// sub-account results keep default encoding. WSEvent.Raw is not marshaled.
+
+// millisFromTime converts time to millisecond timestamp, zero time becomes 0.
+func millisFromTime(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return unixMillis(t)
+}
+
+type kline Kline
+
+// MarshalJSON encodes Kline with times as millisecond timestamps.
+func (k Kline) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		kline
+		OpenTime  int64
+		CloseTime int64
+	}{kline(k), millisFromTime(k.OpenTime), millisFromTime(k.CloseTime)})
+}
+
+// UnmarshalJSON decodes Kline encoded by MarshalJSON.
+func (k *Kline) UnmarshalJSON(data []byte) error {
+	raw := &struct {
+		*kline
+		OpenTime  int64
+		CloseTime int64
+	}{kline: (*kline)(k)}
+	if err := json.Unmarshal(data, raw); err != nil {
+		return err
+	}
+	k.OpenTime = timeFromUnixMillis(raw.OpenTime)
+	k.CloseTime = timeFromUnixMillis(raw.CloseTime)
+	return nil
+}
+
+type ticker24 Ticker24
+
+// MarshalJSON encodes Ticker24 with times as millisecond timestamps.
+func (t Ticker24) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		ticker24
+		OpenTime  int64
+		CloseTime int64
+	}{ticker24(t), millisFromTime(t.OpenTime), millisFromTime(t.CloseTime)})
+}
+
+// UnmarshalJSON decodes Ticker24 encoded by MarshalJSON.
+func (t *Ticker24) UnmarshalJSON(data []byte) error {
+	raw := &struct {
+		*ticker24
+		OpenTime  int64
+		CloseTime int64
+	}{ticker24: (*ticker24)(t)}
+	if err := json.Unmarshal(data, raw); err != nil {
+		return err
+	}
+	t.OpenTime = timeFromUnixMillis(raw.OpenTime)
+	t.CloseTime = timeFromUnixMillis(raw.CloseTime)
+	return nil
+}
+
+type aggTrade AggTrade
+
+// MarshalJSON encodes AggTrade with times as millisecond timestamps.
+func (at AggTrade) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		aggTrade
+		Timestamp int64
+	}{aggTrade(at), millisFromTime(at.Timestamp)})
+}
+
+// UnmarshalJSON decodes AggTrade encoded by MarshalJSON.
+func (at *AggTrade) UnmarshalJSON(data []byte) error {
+	raw := &struct {
+		*aggTrade
+		Timestamp int64
+	}{aggTrade: (*aggTrade)(at)}
+	if err := json.Unmarshal(data, raw); err != nil {
+		return err
+	}
+	at.Timestamp = timeFromUnixMillis(raw.Timestamp)
+	return nil
+}
+
+type trade Trade
+
+// MarshalJSON encodes Trade with times as millisecond timestamps.
+func (t Trade) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		trade
+		TradeTime int64
+	}{trade(t), millisFromTime(t.TradeTime)})
+}
+
+// UnmarshalJSON decodes Trade encoded by MarshalJSON.
+func (t *Trade) UnmarshalJSON(data []byte) error {
+	raw := &struct {
+		*trade
+		TradeTime int64
+	}{trade: (*trade)(t)}
+	if err := json.Unmarshal(data, raw); err != nil {
+		return err
+	}
+	t.TradeTime = timeFromUnixMillis(raw.TradeTime)
+	return nil
+}
+
+type myTrade MyTrade
+
+// MarshalJSON encodes MyTrade with times as millisecond timestamps.
+func (mt MyTrade) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		myTrade
+		Time int64
+	}{myTrade(mt), millisFromTime(mt.Time)})
+}
+
+// UnmarshalJSON decodes MyTrade encoded by MarshalJSON.
+func (mt *MyTrade) UnmarshalJSON(data []byte) error {
+	raw := &struct {
+		*myTrade
+		Time int64
+	}{myTrade: (*myTrade)(mt)}
+	if err := json.Unmarshal(data, raw); err != nil {
+		return err
+	}
+	mt.Time = timeFromUnixMillis(raw.Time)
+	return nil
+}
+
+type processedOrder ProcessedOrder
+
+// MarshalJSON encodes ProcessedOrder with times as millisecond timestamps.
+func (po ProcessedOrder) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		processedOrder
+		TransactTime int64
+	}{processedOrder(po), millisFromTime(po.TransactTime)})
+}
+
+// UnmarshalJSON decodes ProcessedOrder encoded by MarshalJSON.
+func (po *ProcessedOrder) UnmarshalJSON(data []byte) error {
+	raw := &struct {
+		*processedOrder
+		TransactTime int64
+	}{processedOrder: (*processedOrder)(po)}
+	if err := json.Unmarshal(data, raw); err != nil {
+		return err
+	}
+	po.TransactTime = timeFromUnixMillis(raw.TransactTime)
+	return nil
+}
+
+type executedOrder ExecutedOrder
+
+// MarshalJSON encodes ExecutedOrder with times as millisecond timestamps.
+func (eo ExecutedOrder) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		executedOrder
+		Time       int64
+		UpdateTime int64
+	}{executedOrder(eo), millisFromTime(eo.Time), millisFromTime(eo.UpdateTime)})
+}
+
+// UnmarshalJSON decodes ExecutedOrder encoded by MarshalJSON.
+func (eo *ExecutedOrder) UnmarshalJSON(data []byte) error {
+	raw := &struct {
+		*executedOrder
+		Time       int64
+		UpdateTime int64
+	}{executedOrder: (*executedOrder)(eo)}
+	if err := json.Unmarshal(data, raw); err != nil {
+		return err
+	}
+	eo.Time = timeFromUnixMillis(raw.Time)
+	eo.UpdateTime = timeFromUnixMillis(raw.UpdateTime)
+	return nil
+}
+
+type listStatus ListStatus
+
+// MarshalJSON encodes ListStatus with times as millisecond timestamps.
+func (ls ListStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		listStatus
+		TransactionTime int64
+	}{listStatus(ls), millisFromTime(ls.TransactionTime)})
+}
+
+// UnmarshalJSON decodes ListStatus encoded by MarshalJSON.
+func (ls *ListStatus) UnmarshalJSON(data []byte) error {
+	raw := &struct {
+		*listStatus
+		TransactionTime int64
+	}{listStatus: (*listStatus)(ls)}
+	if err := json.Unmarshal(data, raw); err != nil {
+		return err
+	}
+	ls.TransactionTime = timeFromUnixMillis(raw.TransactionTime)
+	return nil
+}
+
+type balanceUpdate BalanceUpdate
+
+// MarshalJSON encodes BalanceUpdate with times as millisecond timestamps.
+func (bu BalanceUpdate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		balanceUpdate
+		ClearTime int64
+	}{balanceUpdate(bu), millisFromTime(bu.ClearTime)})
+}
+
+// UnmarshalJSON decodes BalanceUpdate encoded by MarshalJSON.
+func (bu *BalanceUpdate) UnmarshalJSON(data []byte) error {
+	raw := &struct {
+		*balanceUpdate
+		ClearTime int64
+	}{balanceUpdate: (*balanceUpdate)(bu)}
+	if err := json.Unmarshal(data, raw); err != nil {
+		return err
+	}
+	bu.ClearTime = timeFromUnixMillis(raw.ClearTime)
+	return nil
+}
+
+type accountPosition AccountPosition
+
+// MarshalJSON encodes AccountPosition with times as millisecond timestamps.
+func (ap AccountPosition) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		accountPosition
+		LastUpdateTime int64
+	}{accountPosition(ap), millisFromTime(ap.LastUpdateTime)})
+}
+
+// UnmarshalJSON decodes AccountPosition encoded by MarshalJSON.
+func (ap *AccountPosition) UnmarshalJSON(data []byte) error {
+	raw := &struct {
+		*accountPosition
+		LastUpdateTime int64
+	}{accountPosition: (*accountPosition)(ap)}
+	if err := json.Unmarshal(data, raw); err != nil {
+		return err
+	}
+	ap.LastUpdateTime = timeFromUnixMillis(raw.LastUpdateTime)
+	return nil
+}
+
+// Events embed WSEvent next to types above, which would otherwise promote
+// their MarshalJSON and drop event fields, so events merge JSON objects of
+// their parts instead.
+
+// wsEventJSON is JSON representation of WSEvent.
+type wsEventJSON struct {
+	Type   string
+	Time   int64
+	Symbol string
+}
+
+func newWSEventJSON(e WSEvent) wsEventJSON {
+	return wsEventJSON{
+		Type:   e.Type,
+		Time:   millisFromTime(e.Time),
+		Symbol: e.Symbol,
+	}
+}
+
+func (e wsEventJSON) event() WSEvent {
+	return WSEvent{
+		Type:   e.Type,
+		Time:   timeFromUnixMillis(e.Time),
+		Symbol: e.Symbol,
+	}
+}
+
+// marshalObjects marshals parts and merges resulting JSON objects into one.
+func marshalObjects(parts ...interface{}) ([]byte, error) {
+	merged := make(map[string]json.RawMessage)
+	for _, part := range parts {
+		data, err := json.Marshal(part)
+		if err != nil {
+			return nil, err
+		}
+		fields := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, err
+		}
+		for k, v := range fields {
+			merged[k] = v
+		}
+	}
+	return json.Marshal(merged)
+}
+
+// unmarshalObjects unmarshals the same JSON object into every part.
+func unmarshalObjects(data []byte, parts ...interface{}) error {
+	for _, part := range parts {
+		if err := json.Unmarshal(data, part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type klineEventJSON struct {
+	Interval     Interval
+	FirstTradeID int64
+	LastTradeID  int64
+	Final        bool
+}
+
+// MarshalJSON encodes KlineEvent with times as millisecond timestamps.
+func (ke KlineEvent) MarshalJSON() ([]byte, error) {
+	return marshalObjects(newWSEventJSON(ke.WSEvent), klineEventJSON{
+		Interval:     ke.Interval,
+		FirstTradeID: ke.FirstTradeID,
+		LastTradeID:  ke.LastTradeID,
+		Final:        ke.Final,
+	}, ke.Kline)
+}
+
+// UnmarshalJSON decodes KlineEvent encoded by MarshalJSON.
+func (ke *KlineEvent) UnmarshalJSON(data []byte) error {
+	var wse wsEventJSON
+	var kej klineEventJSON
+	if err := unmarshalObjects(data, &wse, &kej, &ke.Kline); err != nil {
+		return err
+	}
+	ke.WSEvent = wse.event()
+	ke.Interval = kej.Interval
+	ke.FirstTradeID = kej.FirstTradeID
+	ke.LastTradeID = kej.LastTradeID
+	ke.Final = kej.Final
+	return nil
+}
+
+// MarshalJSON encodes AggTradeEvent with times as millisecond timestamps.
+func (ae AggTradeEvent) MarshalJSON() ([]byte, error) {
+	return marshalObjects(newWSEventJSON(ae.WSEvent), ae.AggTrade)
+}
+
+// UnmarshalJSON decodes AggTradeEvent encoded by MarshalJSON.
+func (ae *AggTradeEvent) UnmarshalJSON(data []byte) error {
+	var wse wsEventJSON
+	if err := unmarshalObjects(data, &wse, &ae.AggTrade); err != nil {
+		return err
+	}
+	ae.WSEvent = wse.event()
+	return nil
+}
+
+// MarshalJSON encodes TradeEvent with times as millisecond timestamps.
+func (te TradeEvent) MarshalJSON() ([]byte, error) {
+	return marshalObjects(newWSEventJSON(te.WSEvent), te.Trade)
+}
+
+// UnmarshalJSON decodes TradeEvent encoded by MarshalJSON.
+func (te *TradeEvent) UnmarshalJSON(data []byte) error {
+	var wse wsEventJSON
+	if err := unmarshalObjects(data, &wse, &te.Trade); err != nil {
+		return err
+	}
+	te.WSEvent = wse.event()
+	return nil
+}
+
+type depthEventJSON struct {
+	FirstUpdateID int
+	UpdateID      int
+	PrevUpdateID  int
+	IsSnapshot    bool
+}
+
+// MarshalJSON encodes DepthEvent with times as millisecond timestamps.
+func (de DepthEvent) MarshalJSON() ([]byte, error) {
+	return marshalObjects(newWSEventJSON(de.WSEvent), depthEventJSON{
+		FirstUpdateID: de.FirstUpdateID,
+		UpdateID:      de.UpdateID,
+		PrevUpdateID:  de.PrevUpdateID,
+		IsSnapshot:    de.IsSnapshot,
+	}, de.OrderBook)
+}
+
+// UnmarshalJSON decodes DepthEvent encoded by MarshalJSON.
+func (de *DepthEvent) UnmarshalJSON(data []byte) error {
+	var wse wsEventJSON
+	var dej depthEventJSON
+	if err := unmarshalObjects(data, &wse, &dej, &de.OrderBook); err != nil {
+		return err
+	}
+	de.WSEvent = wse.event()
+	de.FirstUpdateID = dej.FirstUpdateID
+	de.UpdateID = dej.UpdateID
+	de.PrevUpdateID = dej.PrevUpdateID
+	de.IsSnapshot = dej.IsSnapshot
+	return nil
+}
+
+type accountEventJSON struct {
+	ListStatus       *ListStatus
+	BalanceUpdate    *BalanceUpdate
+	AccountPosition  *AccountPosition
+	ExecutionReport  *ExecutionReportEvent
+	ListenKeyExpired *ListenKeyExpired
+}
+
+// MarshalJSON encodes AccountEvent with times as millisecond timestamps.
+func (ae AccountEvent) MarshalJSON() ([]byte, error) {
+	return marshalObjects(newWSEventJSON(ae.WSEvent), ae.Account, accountEventJSON{
+		ListStatus:       ae.ListStatus,
+		BalanceUpdate:    ae.BalanceUpdate,
+		AccountPosition:  ae.AccountPosition,
+		ExecutionReport:  ae.ExecutionReport,
+		ListenKeyExpired: ae.ListenKeyExpired,
+	})
+}
+
+// UnmarshalJSON decodes AccountEvent encoded by MarshalJSON.
+func (ae *AccountEvent) UnmarshalJSON(data []byte) error {
+	var wse wsEventJSON
+	var aej accountEventJSON
+	if err := unmarshalObjects(data, &wse, &ae.Account, &aej); err != nil {
+		return err
+	}
+	ae.WSEvent = wse.event()
+	ae.ListStatus = aej.ListStatus
+	ae.BalanceUpdate = aej.BalanceUpdate
+	ae.AccountPosition = aej.AccountPosition
+	ae.ExecutionReport = aej.ExecutionReport
+	ae.ListenKeyExpired = aej.ListenKeyExpired
+	return nil
+}
+
+type markPriceEventJSON struct {
+	MarkPrice            float64
+	IndexPrice           float64
+	EstimatedSettlePrice float64
+	FundingRate          float64
+	NextFundingTime      int64
+}
+
+// MarshalJSON encodes MarkPriceEvent with times as millisecond timestamps.
+func (me MarkPriceEvent) MarshalJSON() ([]byte, error) {
+	return marshalObjects(newWSEventJSON(me.WSEvent), markPriceEventJSON{
+		MarkPrice:            me.MarkPrice,
+		IndexPrice:           me.IndexPrice,
+		EstimatedSettlePrice: me.EstimatedSettlePrice,
+		FundingRate:          me.FundingRate,
+		NextFundingTime:      millisFromTime(me.NextFundingTime),
+	})
+}
+
+// UnmarshalJSON decodes MarkPriceEvent encoded by MarshalJSON.
+func (me *MarkPriceEvent) UnmarshalJSON(data []byte) error {
+	var wse wsEventJSON
+	var mej markPriceEventJSON
+	if err := unmarshalObjects(data, &wse, &mej); err != nil {
+		return err
+	}
+	me.WSEvent = wse.event()
+	me.MarkPrice = mej.MarkPrice
+	me.IndexPrice = mej.IndexPrice
+	me.EstimatedSettlePrice = mej.EstimatedSettlePrice
+	me.FundingRate = mej.FundingRate
+	me.NextFundingTime = timeFromUnixMillis(mej.NextFundingTime)
+	return nil
+}
@@ -0,0 +1,92 @@
+package binance
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	at := time.Date(2020, 9, 10, 11, 12, 13, 14e6, time.UTC)
+	ms := `1599736333014`
+	event := WSEvent{Type: "test", Time: at, Symbol: "BNBBTC"}
+	tests := []struct {
+		name string
+		in   interface{}
+		out  interface{}
+	}{
+		{"Kline", &Kline{OpenTime: at, CloseTime: at.Add(time.Minute), Close: 1.5, RawClose: "1.5"}, &Kline{}},
+		{"Ticker24", &Ticker24{OpenTime: at, CloseTime: at, LastPrice: 4}, &Ticker24{}},
+		{"AggTrade", &AggTrade{ID: 1, Timestamp: at, Price: 0.1}, &AggTrade{}},
+		{"Trade", &Trade{ID: 1, TradeTime: at}, &Trade{}},
+		{"MyTrade", &MyTrade{ID: 1, Time: at}, &MyTrade{}},
+		{"ProcessedOrder", &ProcessedOrder{OrderID: 1, TransactTime: at}, &ProcessedOrder{}},
+		{"ExecutedOrder", &ExecutedOrder{OrderID: 1, Time: at, UpdateTime: at}, &ExecutedOrder{}},
+		{"ListStatus", &ListStatus{OrderListID: 1, TransactionTime: at}, &ListStatus{}},
+		{"KlineEvent", &KlineEvent{
+			WSEvent:  event,
+			Interval: Minute,
+			Final:    true,
+			Kline:    Kline{OpenTime: at, CloseTime: at.Add(time.Minute)},
+		}, &KlineEvent{}},
+		{"AggTradeEvent", &AggTradeEvent{WSEvent: event, AggTrade: AggTrade{ID: 2, Timestamp: at}}, &AggTradeEvent{}},
+		{"TradeEvent", &TradeEvent{WSEvent: event, Trade: Trade{ID: 3, TradeTime: at}}, &TradeEvent{}},
+		{"DepthEvent", &DepthEvent{
+			WSEvent:       event,
+			FirstUpdateID: 1,
+			UpdateID:      2,
+			PrevUpdateID:  0,
+			IsSnapshot:    true,
+			OrderBook: OrderBook{
+				LastUpdateID: 2,
+				Bids:         []*Order{{Price: 1, Quantity: 2, RawPrice: "1", RawQuantity: "2"}},
+				Asks:         []*Order{{Price: 3, Quantity: 0, RawPrice: "3", RawQuantity: "0"}},
+			},
+		}, &DepthEvent{}},
+		{"AccountEvent", &AccountEvent{
+			WSEvent:         event,
+			Account:         Account{CanTrade: true, Balances: []*Balance{{Asset: "BTC", Free: 1.5, RawFree: "1.5", RawLocked: "0"}}},
+			ListStatus:      &ListStatus{OrderListID: 1, TransactionTime: at},
+			BalanceUpdate:   &BalanceUpdate{Asset: "BTC", Delta: 0.5, ClearTime: at},
+			AccountPosition: &AccountPosition{LastUpdateTime: at, Balances: []*Balance{{Asset: "BNB", Locked: 2, RawFree: "0", RawLocked: "2"}}},
+			ExecutionReport: &ExecutionReportEvent{
+				Type:            "executionReport",
+				EventTime:       1599736333014,
+				Symbol:          "BNBBTC",
+				CommissionAsset: "BNB",
+				TransactionTime: 1599736333014,
+				OrderListID:     -1,
+			},
+			ListenKeyExpired: &ListenKeyExpired{ListenKey: "key"},
+		}, &AccountEvent{}},
+		{"MarkPriceEvent", &MarkPriceEvent{
+			WSEvent:         event,
+			MarkPrice:       11794.15,
+			FundingRate:     0.00038167,
+			NextFundingTime: at,
+		}, &MarkPriceEvent{}},
+		{"zero time", &Trade{ID: 4}, &Trade{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(data), "2020-09-10T") {
+				t.Errorf("time encoded as RFC 3339: %s", data)
+			}
+			if tt.name != "zero time" && !strings.Contains(string(data), ms) {
+				t.Errorf("millisecond timestamp %s missing: %s", ms, data)
+			}
+			if err := json.Unmarshal(data, tt.out); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tt.in, tt.out) {
+				t.Errorf("round trip mismatch\n got: %+v\nwant: %+v\njson: %s", tt.out, tt.in, data)
+			}
+		})
+	}
+}
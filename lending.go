@@ -0,0 +1,73 @@
+package binance
+
+import "time"
+
+// LendingProductListRequest represents LendingProductList request data.
+type LendingProductListRequest struct {
+	Asset      string
+	Current    int
+	Size       int
+	RecvWindow time.Duration
+	Timestamp  time.Time
+}
+
+// LendingProduct represents flexible savings (Simple Earn) product.
+type LendingProduct struct {
+	ProductID                  string  `json:"productId"`
+	Asset                      string  `json:"asset"`
+	LatestAnnualPercentageRate float64 `json:"latestAnnualPercentageRate,string"`
+	MinPurchaseAmount          float64 `json:"minPurchaseAmount,string"`
+	CanPurchase                bool    `json:"canPurchase"`
+	CanRedeem                  bool    `json:"canRedeem"`
+	IsSoldOut                  bool    `json:"isSoldOut"`
+	Status                     string  `json:"status"`
+}
+
+// LendingProductList lists flexible savings products.
+func (b *binance) LendingProductList(lplr LendingProductListRequest) ([]*LendingProduct, error) {
+	return b.Service.LendingProductList(lplr)
+}
+
+// LendingPurchaseRequest represents LendingPurchase request data.
+type LendingPurchaseRequest struct {
+	ProductID     string
+	Amount        float64
+	AutoSubscribe *bool
+	RecvWindow    time.Duration
+	Timestamp     time.Time
+}
+
+// LendingPurchaseResult represents LendingPurchase result.
+type LendingPurchaseResult struct {
+	PurchaseID int64
+	Success    bool
+}
+
+// LendingPurchase subscribes amount to flexible savings product.
+func (b *binance) LendingPurchase(lpr LendingPurchaseRequest) (*LendingPurchaseResult, error) {
+	return b.Service.LendingPurchase(lpr)
+}
+
+// LendingRedeemRequest represents LendingRedeem request data.
+//
+// Either RedeemAll or Amount has to be provided. DestAccount is SPOT or FUND
+// and defaults to SPOT when empty.
+type LendingRedeemRequest struct {
+	ProductID   string
+	RedeemAll   bool
+	Amount      float64
+	DestAccount string
+	RecvWindow  time.Duration
+	Timestamp   time.Time
+}
+
+// LendingRedeemResult represents LendingRedeem result.
+type LendingRedeemResult struct {
+	RedeemID int64
+	Success  bool
+}
+
+// LendingRedeem redeems amount from flexible savings product.
+func (b *binance) LendingRedeem(lrr LendingRedeemRequest) (*LendingRedeemResult, error) {
+	return b.Service.LendingRedeem(lrr)
+}
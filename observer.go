@@ -0,0 +1,71 @@
+package binance
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Observer is notified about service activity, e.g. to feed request latency,
+// error rates and used weight to metrics system.
+//
+// Methods are called synchronously from request and stream goroutines, so
+// implementation has to be safe for concurrent use and return quickly.
+// Embed NopObserver to implement only some of methods.
+type Observer interface {
+	// RequestStarted is called before REST request is sent.
+	RequestStarted(method, endpoint string)
+	// RequestFinished is called when REST response is received or request
+	// failed with err. Status is zero if no response was received.
+	RequestFinished(method, endpoint string, status int, duration time.Duration, err error)
+	// APIError is called for every error response with Binance error code.
+	APIError(code int)
+	// UsedWeight is called with request weight used within interval (e.g.
+	// "1M") as reported by X-MBX-USED-WEIGHT-* headers.
+	UsedWeight(interval string, weight int)
+	// WebsocketConnected is called when websocket connection is opened.
+	WebsocketConnected(url string)
+	// WebsocketDisconnected is called when websocket connection is closed,
+	// err is nil if it was closed by context cancellation.
+	WebsocketDisconnected(url string, err error)
+}
+
+// ResponseHook is called with raw body of every REST response before it's
+// parsed, e.g. to persist exact exchange responses for audit or to read
+// fields not parsed by the library. Like Observer, it's called synchronously
+// and has to be safe for concurrent use. Body must not be modified.
+type ResponseHook func(method, endpoint string, status int, body []byte)
+
+// NopObserver is Observer doing nothing.
+type NopObserver struct{}
+
+func (NopObserver) RequestStarted(method, endpoint string) {}
+
+func (NopObserver) RequestFinished(method, endpoint string, status int, duration time.Duration, err error) {
+}
+
+func (NopObserver) APIError(code int) {}
+
+func (NopObserver) UsedWeight(interval string, weight int) {}
+
+func (NopObserver) WebsocketConnected(url string) {}
+
+func (NopObserver) WebsocketDisconnected(url string, err error) {}
+
+const usedWeightHeaderPrefix = "X-Mbx-Used-Weight-"
+
+// observeUsedWeight reports X-MBX-USED-WEIGHT-* response headers, e.g.
+// X-MBX-USED-WEIGHT-1M, to observer.
+func (as *apiService) observeUsedWeight(header http.Header) {
+	for key, values := range header {
+		if !strings.HasPrefix(key, usedWeightHeaderPrefix) || len(values) == 0 {
+			continue
+		}
+		weight, err := strconv.Atoi(values[0])
+		if err != nil {
+			continue
+		}
+		as.observer.UsedWeight(strings.ToUpper(strings.TrimPrefix(key, usedWeightHeaderPrefix)), weight)
+	}
+}
@@ -20,6 +20,15 @@ var (
 
 	TypeLimit  = OrderType("LIMIT")
 	TypeMarket = OrderType("MARKET")
+	// TypeLimitMaker is post-only limit order, rejected if it would
+	// immediately match.
+	TypeLimitMaker = OrderType("LIMIT_MAKER")
+	// Stop loss and take profit orders are placed as market or limit order
+	// once StopPrice is reached.
+	TypeStopLoss        = OrderType("STOP_LOSS")
+	TypeStopLossLimit   = OrderType("STOP_LOSS_LIMIT")
+	TypeTakeProfit      = OrderType("TAKE_PROFIT")
+	TypeTakeProfitLimit = OrderType("TAKE_PROFIT_LIMIT")
 
 	SideBuy  = OrderSide("BUY")
 	SideSell = OrderSide("SELL")
@@ -0,0 +1,160 @@
+package binance
+
+import "sort"
+
+// ApplyDepthDelta returns copy of book with updates of depth event ev
+// applied, book is left unchanged. Price levels with zero quantity are
+// removed, others are updated or inserted, keeping bids sorted by price
+// descending and asks ascending.
+//
+// Events not newer than the book, i.e. with UpdateID not greater than
+// book's LastUpdateID, are ignored. Checking events for gaps is up to
+// caller, see DepthEvent.
+func ApplyDepthDelta(book *OrderBook, ev *DepthEvent) *OrderBook {
+	nb := &OrderBook{
+		LastUpdateID: book.LastUpdateID,
+		Bids:         copyLevels(book.Bids),
+		Asks:         copyLevels(book.Asks),
+	}
+	if ev.UpdateID <= book.LastUpdateID {
+		return nb
+	}
+	for _, bid := range ev.Bids {
+		nb.Bids = upsertLevel(nb.Bids, *bid, true)
+	}
+	for _, ask := range ev.Asks {
+		nb.Asks = upsertLevel(nb.Asks, *ask, false)
+	}
+	nb.LastUpdateID = ev.UpdateID
+	return nb
+}
+
+// Removals returns prices of bid and ask levels which the event removes,
+// i.e. levels with zero quantity. Other levels of Bids and Asks are updates.
+func (ev *DepthEvent) Removals() (bids, asks []float64) {
+	return removedPrices(ev.Bids), removedPrices(ev.Asks)
+}
+
+func removedPrices(levels []*Order) []float64 {
+	var prices []float64
+	for _, o := range levels {
+		if o.Quantity == 0 {
+			prices = append(prices, o.Price)
+		}
+	}
+	return prices
+}
+
+// UpsertBid sets quantity of bid price level, keeping bids sorted by price
+// descending, so the best bid is the first one. Zero quantity removes the
+// level.
+func (ob *OrderBook) UpsertBid(price, quantity float64) {
+	ob.Bids = upsertLevel(ob.Bids, Order{Price: price, Quantity: quantity}, true)
+}
+
+// UpsertAsk sets quantity of ask price level, keeping asks sorted by price
+// ascending, so the best ask is the first one. Zero quantity removes the
+// level.
+func (ob *OrderBook) UpsertAsk(price, quantity float64) {
+	ob.Asks = upsertLevel(ob.Asks, Order{Price: price, Quantity: quantity}, false)
+}
+
+func copyLevels(levels []*Order) []*Order {
+	c := make([]*Order, len(levels))
+	for i, o := range levels {
+		order := *o
+		c[i] = &order
+	}
+	return c
+}
+
+// upsertLevel sets quantity of price level of o in levels sorted by price,
+// descending if desc is set, removing level of zero quantity.
+func upsertLevel(levels []*Order, o Order, desc bool) []*Order {
+	price, quantity := o.Price, o.Quantity
+	i := sort.Search(len(levels), func(i int) bool {
+		if desc {
+			return levels[i].Price <= price
+		}
+		return levels[i].Price >= price
+	})
+	found := i < len(levels) && levels[i].Price == price
+	switch {
+	case quantity == 0 && found:
+		return append(levels[:i], levels[i+1:]...)
+	case quantity == 0:
+		return levels
+	case found:
+		levels[i].Quantity = quantity
+		levels[i].RawQuantity = o.RawQuantity
+		return levels
+	}
+	levels = append(levels, nil)
+	copy(levels[i+1:], levels[i:])
+	levels[i] = &o
+	return levels
+}
+
+// BestBid returns the highest bid, false if there are no bids.
+func (ob *OrderBook) BestBid() (*Order, bool) {
+	if len(ob.Bids) == 0 {
+		return nil, false
+	}
+	return ob.Bids[0], true
+}
+
+// BestAsk returns the lowest ask, false if there are no asks.
+func (ob *OrderBook) BestAsk() (*Order, bool) {
+	if len(ob.Asks) == 0 {
+		return nil, false
+	}
+	return ob.Asks[0], true
+}
+
+// MidPrice returns price halfway between the best bid and ask, false if
+// either side is empty.
+func (ob *OrderBook) MidPrice() (float64, bool) {
+	bid, okBid := ob.BestBid()
+	ask, okAsk := ob.BestAsk()
+	if !okBid || !okAsk {
+		return 0, false
+	}
+	return (bid.Price + ask.Price) / 2, true
+}
+
+// Spread returns difference between the best ask and bid, false if either
+// side is empty.
+func (ob *OrderBook) Spread() (float64, bool) {
+	bid, okBid := ob.BestBid()
+	ask, okAsk := ob.BestAsk()
+	if !okBid || !okAsk {
+		return 0, false
+	}
+	return ask.Price - bid.Price, true
+}
+
+// VWAP returns average price of filling quantity by walking the book, asks
+// for buying and bids for selling. If the side doesn't hold enough, average
+// price of the whole side is returned, with filled lower than quantity.
+func (ob *OrderBook) VWAP(side OrderSide, quantity float64) (avgPrice float64, filled float64) {
+	levels := ob.Asks
+	if side == SideSell {
+		levels = ob.Bids
+	}
+	var notional, qty kahanSum
+	for _, o := range levels {
+		if qty.sum >= quantity {
+			break
+		}
+		q := o.Quantity
+		if rest := quantity - qty.sum; q > rest {
+			q = rest
+		}
+		notional.add(q * o.Price)
+		qty.add(q)
+	}
+	if qty.sum == 0 {
+		return 0, 0
+	}
+	return notional.sum / qty.sum, qty.sum
+}
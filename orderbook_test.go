@@ -0,0 +1,236 @@
+package binance
+
+import (
+	"fmt"
+	"testing"
+)
+
+// testLevels returns price levels of price, quantity pairs pq.
+func testLevels(pq ...float64) []*Order {
+	levels := make([]*Order, 0, len(pq)/2)
+	for i := 0; i+1 < len(pq); i += 2 {
+		levels = append(levels, &Order{Price: pq[i], Quantity: pq[i+1]})
+	}
+	return levels
+}
+
+func formatLevels(levels []*Order) string {
+	s := "["
+	for i, o := range levels {
+		if i > 0 {
+			s += " "
+		}
+		s += fmt.Sprintf("%v:%v", o.Price, o.Quantity)
+	}
+	return s + "]"
+}
+
+func TestApplyDepthDelta(t *testing.T) {
+	book := func() *OrderBook {
+		return &OrderBook{
+			LastUpdateID: 10,
+			Bids:         testLevels(3, 1, 2, 1, 1, 1),
+			Asks:         testLevels(4, 1, 5, 1, 6, 1),
+		}
+	}
+	tests := []struct {
+		name     string
+		updateID int
+		bids     []*Order
+		asks     []*Order
+		wantID   int
+		wantBids []*Order
+		wantAsks []*Order
+	}{
+		{
+			name:     "stale event",
+			updateID: 10,
+			bids:     testLevels(2, 0, 2.5, 1),
+			asks:     testLevels(5, 0),
+			wantID:   10,
+			wantBids: testLevels(3, 1, 2, 1, 1, 1),
+			wantAsks: testLevels(4, 1, 5, 1, 6, 1),
+		},
+		{
+			name:     "older event",
+			updateID: 9,
+			bids:     testLevels(3, 7),
+			wantID:   10,
+			wantBids: testLevels(3, 1, 2, 1, 1, 1),
+			wantAsks: testLevels(4, 1, 5, 1, 6, 1),
+		},
+		{
+			name:     "remove level of zero quantity",
+			updateID: 11,
+			bids:     testLevels(2, 0),
+			asks:     testLevels(4, 0, 6, 0),
+			wantID:   11,
+			wantBids: testLevels(3, 1, 1, 1),
+			wantAsks: testLevels(5, 1),
+		},
+		{
+			name:     "zero quantity of missing level",
+			updateID: 11,
+			bids:     testLevels(2.5, 0, 0.5, 0),
+			asks:     testLevels(7, 0),
+			wantID:   11,
+			wantBids: testLevels(3, 1, 2, 1, 1, 1),
+			wantAsks: testLevels(4, 1, 5, 1, 6, 1),
+		},
+		{
+			name:     "update quantity",
+			updateID: 12,
+			bids:     testLevels(3, 2),
+			asks:     testLevels(6, 3),
+			wantID:   12,
+			wantBids: testLevels(3, 2, 2, 1, 1, 1),
+			wantAsks: testLevels(4, 1, 5, 1, 6, 3),
+		},
+		{
+			name:     "insert keeping sides sorted",
+			updateID: 12,
+			bids:     testLevels(2.5, 2, 0.5, 3, 3.5, 4),
+			asks:     testLevels(5.5, 2, 3.5, 3, 7, 4),
+			wantID:   12,
+			wantBids: testLevels(3.5, 4, 3, 1, 2.5, 2, 2, 1, 1, 1, 0.5, 3),
+			wantAsks: testLevels(3.5, 3, 4, 1, 5, 1, 5.5, 2, 6, 1, 7, 4),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ev := &DepthEvent{UpdateID: tt.updateID}
+			ev.Bids = tt.bids
+			ev.Asks = tt.asks
+			got := ApplyDepthDelta(book(), ev)
+			if got.LastUpdateID != tt.wantID {
+				t.Errorf("LastUpdateID = %d, want %d", got.LastUpdateID, tt.wantID)
+			}
+			if g, w := formatLevels(got.Bids), formatLevels(tt.wantBids); g != w {
+				t.Errorf("Bids = %s, want %s", g, w)
+			}
+			if g, w := formatLevels(got.Asks), formatLevels(tt.wantAsks); g != w {
+				t.Errorf("Asks = %s, want %s", g, w)
+			}
+		})
+	}
+}
+
+func TestApplyDepthDeltaLeavesBookUnchanged(t *testing.T) {
+	book := &OrderBook{
+		LastUpdateID: 10,
+		Bids:         testLevels(3, 1, 2, 1, 1, 1),
+		Asks:         testLevels(4, 1, 5, 1, 6, 1),
+	}
+	bids, asks := formatLevels(book.Bids), formatLevels(book.Asks)
+	ev := &DepthEvent{UpdateID: 11}
+	ev.Bids = testLevels(3, 0, 2, 5, 1.5, 1)
+	ev.Asks = testLevels(4, 0, 5, 5, 5.5, 1)
+
+	got := ApplyDepthDelta(book, ev)
+	if book.LastUpdateID != 10 {
+		t.Errorf("book LastUpdateID = %d, want 10", book.LastUpdateID)
+	}
+	if g := formatLevels(book.Bids); g != bids {
+		t.Errorf("book Bids = %s, want %s", g, bids)
+	}
+	if g := formatLevels(book.Asks); g != asks {
+		t.Errorf("book Asks = %s, want %s", g, asks)
+	}
+	// levels of result must not be shared with book
+	got.Bids[0].Quantity = 100
+	got.Asks[0].Quantity = 100
+	if g := formatLevels(book.Bids); g != bids {
+		t.Errorf("book Bids = %s after changing result, want %s", g, bids)
+	}
+	if g := formatLevels(book.Asks); g != asks {
+		t.Errorf("book Asks = %s after changing result, want %s", g, asks)
+	}
+}
+
+func TestUpsertLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		levels   []*Order
+		price    float64
+		quantity float64
+		desc     bool
+		want     []*Order
+	}{
+		{name: "insert into empty", price: 1, quantity: 2, want: testLevels(1, 2)},
+		{name: "remove from empty", price: 1},
+		{
+			name:     "insert first ascending",
+			levels:   testLevels(2, 1, 3, 1),
+			price:    1,
+			quantity: 2,
+			want:     testLevels(1, 2, 2, 1, 3, 1),
+		},
+		{
+			name:     "insert middle ascending",
+			levels:   testLevels(1, 1, 3, 1),
+			price:    2,
+			quantity: 2,
+			want:     testLevels(1, 1, 2, 2, 3, 1),
+		},
+		{
+			name:     "insert last ascending",
+			levels:   testLevels(1, 1, 2, 1),
+			price:    3,
+			quantity: 2,
+			want:     testLevels(1, 1, 2, 1, 3, 2),
+		},
+		{
+			name:     "insert first descending",
+			levels:   testLevels(2, 1, 1, 1),
+			price:    3,
+			quantity: 2,
+			desc:     true,
+			want:     testLevels(3, 2, 2, 1, 1, 1),
+		},
+		{
+			name:     "insert middle descending",
+			levels:   testLevels(3, 1, 1, 1),
+			price:    2,
+			quantity: 2,
+			desc:     true,
+			want:     testLevels(3, 1, 2, 2, 1, 1),
+		},
+		{
+			name:     "insert last descending",
+			levels:   testLevels(3, 1, 2, 1),
+			price:    1,
+			quantity: 2,
+			desc:     true,
+			want:     testLevels(3, 1, 2, 1, 1, 2),
+		},
+		{
+			name:     "update",
+			levels:   testLevels(3, 1, 2, 1, 1, 1),
+			price:    2,
+			quantity: 5,
+			desc:     true,
+			want:     testLevels(3, 1, 2, 5, 1, 1),
+		},
+		{
+			name:   "remove",
+			levels: testLevels(1, 1, 2, 1, 3, 1),
+			price:  3,
+			want:   testLevels(1, 1, 2, 1),
+		},
+		{
+			name:   "remove missing",
+			levels: testLevels(3, 1, 1, 1),
+			price:  2,
+			desc:   true,
+			want:   testLevels(3, 1, 1, 1),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := upsertLevel(tt.levels, Order{Price: tt.price, Quantity: tt.quantity}, tt.desc)
+			if g, w := formatLevels(got), formatLevels(tt.want); g != w {
+				t.Errorf("upsertLevel = %s, want %s", g, w)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package binance
+
+import (
+	"sync"
+	"time"
+)
+
+// PriceCache serves latest prices of all symbols from memory, fetching them
+// by single TickerAllPrices request at most once per refresh interval, which
+// weighs far less than requesting prices symbol by symbol.
+//
+// Prices are fetched lazily by Price once they're older than the interval.
+// If fetch fails, previous prices are served and the next fetch is attempted
+// after the interval, see Err. PriceCache is safe for concurrent use.
+type PriceCache struct {
+	service Service
+	refresh time.Duration
+
+	mu        sync.Mutex
+	prices    map[string]float64
+	fetchedAt time.Time
+	err       error
+}
+
+// NewPriceCache returns cache of symbol prices refreshed at most once per
+// refresh interval.
+func (b *binance) NewPriceCache(refresh time.Duration) *PriceCache {
+	return &PriceCache{
+		service: b.Service,
+		refresh: refresh,
+	}
+}
+
+// Price returns the latest price of symbol, false if it's unknown.
+func (pc *PriceCache) Price(symbol string) (float64, bool) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	if time.Since(pc.fetchedAt) >= pc.refresh {
+		pc.fetch()
+	}
+	price, ok := pc.prices[NormalizeSymbol(symbol)]
+	return price, ok
+}
+
+// Refresh fetches prices regardless of refresh interval.
+func (pc *PriceCache) Refresh() error {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.fetch()
+	return pc.err
+}
+
+// Err returns error of the last fetch, nil if it succeeded.
+func (pc *PriceCache) Err() error {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	return pc.err
+}
+
+// fetch requests prices, it has to be called with mu held.
+func (pc *PriceCache) fetch() {
+	pc.fetchedAt = time.Now()
+	tickers, err := pc.service.TickerAllPrices()
+	if err != nil {
+		pc.err = err
+		return
+	}
+	prices := make(map[string]float64, len(tickers))
+	for _, t := range tickers {
+		prices[t.Symbol] = t.Price
+	}
+	pc.prices, pc.err = prices, nil
+}
@@ -0,0 +1,51 @@
+package binance
+
+import "time"
+
+// RateLimitType represents rate limit type enum.
+type RateLimitType string
+
+// RateLimitInterval represents rate limit interval enum.
+type RateLimitInterval string
+
+var (
+	// RateLimitRequestWeight limits sum of weights of requests, see Weight.
+	RateLimitRequestWeight = RateLimitType("REQUEST_WEIGHT")
+	// RateLimitOrders limits number of placed orders.
+	RateLimitOrders = RateLimitType("ORDERS")
+	// RateLimitRawRequests limits number of requests regardless of weight.
+	RateLimitRawRequests = RateLimitType("RAW_REQUESTS")
+
+	RateLimitSecond = RateLimitInterval("SECOND")
+	RateLimitMinute = RateLimitInterval("MINUTE")
+	RateLimitDay    = RateLimitInterval("DAY")
+)
+
+// RateLimit represents limit reported by exchange info, allowing Limit of
+// RateLimitType per IntervalNum of Interval, e.g. 1200 of REQUEST_WEIGHT per
+// 1 MINUTE.
+type RateLimit struct {
+	RateLimitType RateLimitType     `json:"rateLimitType"`
+	Interval      RateLimitInterval `json:"interval"`
+	IntervalNum   int               `json:"intervalNum"`
+	Limit         int               `json:"limit"`
+}
+
+// Duration returns length of window the limit applies to, or zero when
+// interval is unknown.
+func (rl RateLimit) Duration() time.Duration {
+	num := rl.IntervalNum
+	if num == 0 {
+		num = 1
+	}
+	switch rl.Interval {
+	case RateLimitSecond:
+		return time.Duration(num) * time.Second
+	case RateLimitMinute:
+		return time.Duration(num) * time.Minute
+	case RateLimitDay:
+		return time.Duration(num) * 24 * time.Hour
+	default:
+		return 0
+	}
+}
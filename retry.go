@@ -0,0 +1,61 @@
+package binance
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// RetryPolicy configures retrying of REST requests failed transiently.
+//
+// GET requests are retried on network errors and 5xx responses. Requests
+// of other methods, e.g. order placement, are retried only if connection
+// couldn't be established, so they never reach Binance twice.
+type RetryPolicy struct {
+	// MaxAttempts limits number of attempts including the first one, values
+	// lower than 2 disable retrying.
+	MaxAttempts int
+	// Backoff is delay before the first retry, doubled for every next one.
+	Backoff time.Duration
+	// MaxBackoff limits delay between retries, zero means no limit.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy is used by service unless WithRetryPolicy is provided.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	Backoff:     500 * time.Millisecond,
+	MaxBackoff:  5 * time.Second,
+}
+
+// shouldRetry reports whether request of method failed with resp or err
+// can be retried safely.
+func (p RetryPolicy) shouldRetry(method string, resp *http.Response, err error) bool {
+	if err != nil {
+		return method == "GET" || isDialError(err)
+	}
+	return method == "GET" && resp.StatusCode >= 500
+}
+
+// delay returns delay before retry following given attempt.
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	d := p.Backoff
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if p.MaxBackoff != 0 && d >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	return d
+}
+
+// isDialError reports whether err occurred before request was sent, because
+// connection couldn't be established.
+func isDialError(err error) bool {
+	if ue, ok := err.(*url.Error); ok {
+		err = ue.Err
+	}
+	oe, ok := err.(*net.OpError)
+	return ok && oe.Op == "dial"
+}
@@ -2,67 +2,162 @@ package binance
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"strconv"
+	"time"
 
-	"fmt"
+	"github.com/go-kit/kit/log/level"
 	"github.com/pkg/errors"
 )
 
 type rawExecutedOrder struct {
-	Symbol        string  `json:"symbol"`
-	OrderID       int     `json:"orderId"`
-	ClientOrderID string  `json:"clientOrderId"`
-	Price         string  `json:"price"`
-	OrigQty       string  `json:"origQty"`
-	ExecutedQty   string  `json:"executedQty"`
-	Status        string  `json:"status"`
-	TimeInForce   string  `json:"timeInForce"`
-	Type          string  `json:"type"`
-	Side          string  `json:"side"`
-	StopPrice     string  `json:"stopPrice"`
-	IcebergQty    string  `json:"icebergQty"`
-	Time          float64 `json:"time"`
+	Symbol        string     `json:"symbol"`
+	OrderID       lenientInt `json:"orderId"`
+	ClientOrderID string     `json:"clientOrderId"`
+	Price         string     `json:"price"`
+	OrigQty       string     `json:"origQty"`
+	ExecutedQty   string     `json:"executedQty"`
+	CumQuoteQty   string     `json:"cummulativeQuoteQty"`
+	Status        string     `json:"status"`
+	TimeInForce   string     `json:"timeInForce"`
+	Type          string     `json:"type"`
+	Side          string     `json:"side"`
+	StopPrice     string     `json:"stopPrice"`
+	IcebergQty    string     `json:"icebergQty"`
+	Time          float64    `json:"time"`
+	UpdateTime    int64      `json:"updateTime"`
+	IsWorking     bool       `json:"isWorking"`
 }
 
-func (as *apiService) NewOrder(or NewOrderRequest) (*ProcessedOrder, error) {
+// newOrderParams validates or if enabled by WithOrderValidation and returns
+// params shared by NewOrder and test order requests.
+func (as *apiService) newOrderParams(or NewOrderRequest) (map[string]string, error) {
+	if as.validateOrders {
+		if err := or.Validate(); err != nil {
+			return nil, err
+		}
+	}
 	params := make(map[string]string)
-	params["symbol"] = or.Symbol
+	params["symbol"] = NormalizeSymbol(or.Symbol)
 	params["side"] = string(or.Side)
 	params["type"] = string(or.Type)
-	params["timeInForce"] = string(or.TimeInForce)
+	if or.TimeInForce != "" {
+		params["timeInForce"] = string(or.TimeInForce)
+	}
 	params["quantity"] = fmt.Sprintf("%.6f", or.Quantity)
-	params["price"] = strconv.FormatFloat(or.Price, 'f', -1, 64)
+	if or.Price != 0 {
+		params["price"] = strconv.FormatFloat(or.Price, 'f', -1, 64)
+	}
 	params["timestamp"] = strconv.FormatInt(unixMillis(or.Timestamp), 10)
-	if or.NewClientOrderID != "" {
-		params["newClientOrderId"] = or.NewClientOrderID
+	clientOrderID, err := as.clientOrderID(or.NewClientOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if clientOrderID != "" {
+		params["newClientOrderId"] = clientOrderID
 	}
 	if or.StopPrice != 0 {
 		params["stopPrice"] = strconv.FormatFloat(or.StopPrice, 'f', -1, 64)
 	}
+	if or.TrailingDelta != 0 {
+		params["trailingDelta"] = strconv.Itoa(or.TrailingDelta)
+	}
 	if or.IcebergQty != 0 {
 		params["icebergQty"] = strconv.FormatFloat(or.IcebergQty, 'f', -1, 64)
 	}
+	return params, nil
+}
 
-	res, err := as.request("POST", "api/v3/order", params, true, true)
+func (as *apiService) NewOrder(or NewOrderRequest) (*ProcessedOrder, error) {
+	params, err := as.newOrderParams(or)
 	if err != nil {
 		return nil, err
 	}
+	if as.orderRecovery && params["newClientOrderId"] != "" {
+		return as.placeOrderRecovering(params)
+	}
+	po, _, err := as.placeOrder(params)
+	return po, err
+}
+
+// placeOrder sends new order request, returned status is 0 if no response
+// was received.
+func (as *apiService) placeOrder(params map[string]string) (*ProcessedOrder, int, error) {
+	res, err := as.request("POST", "api/v3/order", params, true, true)
+	if err != nil {
+		return nil, 0, err
+	}
 	textRes, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return nil, errors.Wrap(err, "unable to read response from Ticker/24hr")
+		return nil, res.StatusCode, errors.Wrap(err, "unable to read response from order.post")
 	}
 	defer res.Body.Close()
 
 	if res.StatusCode != 200 {
-		return nil, as.handleError(textRes)
+		return nil, res.StatusCode, as.handleError(textRes)
+	}
+	po, err := processedOrderFromJSON(textRes)
+	return po, res.StatusCode, err
+}
+
+// errCodeNoSuchOrder is returned by Binance for unknown orders.
+const errCodeNoSuchOrder = -2013
+
+// placeOrderRecovering places order with client order id and if it's unknown
+// whether the order was accepted, looks it up by the id. Order is placed
+// again only if it wasn't found, up to MaxAttempts of retry policy.
+func (as *apiService) placeOrderRecovering(params map[string]string) (*ProcessedOrder, error) {
+	for attempt := 1; ; attempt++ {
+		po, status, err := as.placeOrder(params)
+		if err == ErrCircuitOpen {
+			// the order wasn't sent
+			return nil, err
+		}
+		ambiguous := status >= 500 ||
+			(status == 0 && err != nil && !isDialError(err) && as.Ctx.Err() == nil)
+		if !ambiguous {
+			return po, err
+		}
+		level.Warn(as.Logger).Log("newOrder", "outcome unknown, querying order",
+			"clientOrderId", params["newClientOrderId"], "err", err)
+
+		// give Binance time to finish processing the order
+		timer := time.NewTimer(as.retryPolicy.delay(attempt))
+		select {
+		case <-timer.C:
+		case <-as.Ctx.Done():
+			timer.Stop()
+			return nil, err
+		}
+		eo, qerr := as.QueryOrder(QueryOrderRequest{
+			Symbol:            params["symbol"],
+			OrigClientOrderID: params["newClientOrderId"],
+			Timestamp:         time.Now(),
+		})
+		if qerr == nil {
+			return &ProcessedOrder{
+				Symbol:        eo.Symbol,
+				OrderID:       int64(eo.OrderID),
+				ClientOrderID: eo.ClientOrderID,
+				TransactTime:  eo.Time,
+			}, nil
+		}
+		if e, ok := qerr.(*Error); !ok || e.Code != errCodeNoSuchOrder || attempt >= as.retryPolicy.MaxAttempts {
+			return nil, err
+		}
+		params["timestamp"] = strconv.FormatInt(unixMillis(time.Now()), 10)
 	}
+}
 
+// processedOrderFromJSON parses new order response shared by REST and
+// websocket API.
+func processedOrderFromJSON(textRes []byte) (*ProcessedOrder, error) {
 	rawOrder := struct {
-		Symbol        string  `json:"symbol"`
-		OrderID       int64   `json:"orderId"`
-		ClientOrderID string  `json:"clientOrderId"`
-		TransactTime  float64 `json:"transactTime"`
+		Symbol        string     `json:"symbol"`
+		OrderID       lenientInt `json:"orderId"`
+		ClientOrderID string     `json:"clientOrderId"`
+		TransactTime  float64    `json:"transactTime"`
 	}{}
 	if err := json.Unmarshal(textRes, &rawOrder); err != nil {
 		return nil, errors.Wrap(err, "rawOrder unmarshal failed")
@@ -75,50 +170,81 @@ func (as *apiService) NewOrder(or NewOrderRequest) (*ProcessedOrder, error) {
 
 	return &ProcessedOrder{
 		Symbol:        rawOrder.Symbol,
-		OrderID:       rawOrder.OrderID,
+		OrderID:       int64(rawOrder.OrderID),
 		ClientOrderID: rawOrder.ClientOrderID,
 		TransactTime:  t,
 	}, nil
 }
 
 func (as *apiService) NewOrderTest(or NewOrderRequest) error {
-	params := make(map[string]string)
-	params["symbol"] = or.Symbol
-	params["side"] = string(or.Side)
-	params["type"] = string(or.Type)
-	params["timeInForce"] = string(or.TimeInForce)
-	params["quantity"] = strconv.FormatFloat(or.Quantity, 'f', -1, 64)
-	params["price"] = strconv.FormatFloat(or.Price, 'f', -1, 64)
-	params["timestamp"] = strconv.FormatInt(unixMillis(or.Timestamp), 10)
-	if or.NewClientOrderID != "" {
-		params["newClientOrderId"] = or.NewClientOrderID
-	}
-	if or.StopPrice != 0 {
-		params["stopPrice"] = strconv.FormatFloat(or.StopPrice, 'f', -1, 64)
+	_, err := as.NewOrderTestResult(or)
+	return err
+}
+
+func (as *apiService) NewOrderTestResult(or NewOrderRequest) (*TestOrderResult, error) {
+	params, err := as.newOrderParams(or)
+	if err != nil {
+		return nil, err
 	}
-	if or.IcebergQty != 0 {
-		params["icebergQty"] = strconv.FormatFloat(or.IcebergQty, 'f', -1, 64)
+	if or.ComputeCommissionRates {
+		params["computeCommissionRates"] = "true"
 	}
 
 	res, err := as.request("POST", "api/v3/order/test", params, true, true)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	textRes, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return errors.Wrap(err, "unable to read response from Ticker/24hr")
+		return nil, errors.Wrap(err, "unable to read response from order/test")
 	}
 	defer res.Body.Close()
 
 	if res.StatusCode != 200 {
-		return as.handleError(textRes)
+		return nil, as.handleError(textRes)
 	}
-	return nil
+
+	rawResult := struct {
+		StandardCommissionForOrder struct {
+			Maker float64 `json:"maker,string"`
+			Taker float64 `json:"taker,string"`
+		} `json:"standardCommissionForOrder"`
+		TaxCommissionForOrder struct {
+			Maker float64 `json:"maker,string"`
+			Taker float64 `json:"taker,string"`
+		} `json:"taxCommissionForOrder"`
+		Discount struct {
+			EnabledForAccount bool    `json:"enabledForAccount"`
+			EnabledForSymbol  bool    `json:"enabledForSymbol"`
+			DiscountAsset     string  `json:"discountAsset"`
+			Discount          float64 `json:"discount,string"`
+		} `json:"discount"`
+	}{}
+	if err := json.Unmarshal(textRes, &rawResult); err != nil {
+		return nil, errors.Wrap(err, "rawResult unmarshal failed")
+	}
+
+	return &TestOrderResult{
+		StandardCommissionForOrder: CommissionRates{
+			Maker: rawResult.StandardCommissionForOrder.Maker,
+			Taker: rawResult.StandardCommissionForOrder.Taker,
+		},
+		TaxCommissionForOrder: CommissionRates{
+			Maker: rawResult.TaxCommissionForOrder.Maker,
+			Taker: rawResult.TaxCommissionForOrder.Taker,
+		},
+		Discount: CommissionDiscount{
+			EnabledForAccount: rawResult.Discount.EnabledForAccount,
+			EnabledForSymbol:  rawResult.Discount.EnabledForSymbol,
+			DiscountAsset:     rawResult.Discount.DiscountAsset,
+			Discount:          rawResult.Discount.Discount,
+		},
+	}, nil
 }
 
 func (as *apiService) QueryOrder(qor QueryOrderRequest) (*ExecutedOrder, error) {
 	params := make(map[string]string)
-	params["symbol"] = qor.Symbol
+	params["symbol"] = NormalizeSymbol(qor.Symbol)
 	params["timestamp"] = strconv.FormatInt(unixMillis(qor.Timestamp), 10)
 	if qor.OrderID != 0 {
 		params["orderId"] = strconv.FormatInt(qor.OrderID, 10)
@@ -157,8 +283,25 @@ func (as *apiService) QueryOrder(qor QueryOrderRequest) (*ExecutedOrder, error)
 }
 
 func (as *apiService) CancelOrder(cor CancelOrderRequest) (*CanceledOrder, error) {
+	res, err := as.request("DELETE", "api/v3/order", cancelOrderParams(cor), true, true)
+	if err != nil {
+		return nil, err
+	}
+	textRes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read response from order.delete")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return nil, as.handleError(textRes)
+	}
+	return canceledOrderFromJSON(textRes)
+}
+
+func cancelOrderParams(cor CancelOrderRequest) map[string]string {
 	params := make(map[string]string)
-	params["symbol"] = cor.Symbol
+	params["symbol"] = NormalizeSymbol(cor.Symbol)
 	params["timestamp"] = strconv.FormatInt(unixMillis(cor.Timestamp), 10)
 	if cor.OrderID != 0 {
 		params["orderId"] = strconv.FormatInt(cor.OrderID, 10)
@@ -169,29 +312,21 @@ func (as *apiService) CancelOrder(cor CancelOrderRequest) (*CanceledOrder, error
 	if cor.NewClientOrderID != "" {
 		params["newClientOrderId"] = cor.NewClientOrderID
 	}
+	if cor.CancelRestrictions != "" {
+		params["cancelRestrictions"] = string(cor.CancelRestrictions)
+	}
 	if cor.RecvWindow != 0 {
 		params["recvWindow"] = strconv.FormatInt(recvWindow(cor.RecvWindow), 10)
 	}
+	return params
+}
 
-	res, err := as.request("DELETE", "api/v3/order", params, true, true)
-	if err != nil {
-		return nil, err
-	}
-	textRes, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return nil, errors.Wrap(err, "unable to read response from order.delete")
-	}
-	defer res.Body.Close()
-
-	if res.StatusCode != 200 {
-		return nil, as.handleError(textRes)
-	}
-
+func canceledOrderFromJSON(textRes []byte) (*CanceledOrder, error) {
 	rawCanceledOrder := struct {
-		Symbol            string `json:"symbol"`
-		OrigClientOrderID string `json:"origClientOrderId"`
-		OrderID           int64  `json:"orderId"`
-		ClientOrderID     string `json:"clientOrderId"`
+		Symbol            string     `json:"symbol"`
+		OrigClientOrderID string     `json:"origClientOrderId"`
+		OrderID           lenientInt `json:"orderId"`
+		ClientOrderID     string     `json:"clientOrderId"`
 	}{}
 	if err := json.Unmarshal(textRes, &rawCanceledOrder); err != nil {
 		return nil, errors.Wrap(err, "cancelOrder unmarshal failed")
@@ -200,14 +335,112 @@ func (as *apiService) CancelOrder(cor CancelOrderRequest) (*CanceledOrder, error
 	return &CanceledOrder{
 		Symbol:            rawCanceledOrder.Symbol,
 		OrigClientOrderID: rawCanceledOrder.OrigClientOrderID,
-		OrderID:           rawCanceledOrder.OrderID,
+		OrderID:           int64(rawCanceledOrder.OrderID),
 		ClientOrderID:     rawCanceledOrder.ClientOrderID,
 	}, nil
 }
 
+func (as *apiService) CancelReplaceOrder(crr CancelReplaceRequest) (*CancelReplaceResult, error) {
+	params, err := as.newOrderParams(crr.NewOrderRequest)
+	if err != nil {
+		return nil, err
+	}
+	params["cancelReplaceMode"] = string(crr.CancelReplaceMode)
+	if crr.CancelOrderID != 0 {
+		params["cancelOrderId"] = strconv.FormatInt(crr.CancelOrderID, 10)
+	}
+	if crr.CancelOrigClientOrderID != "" {
+		params["cancelOrigClientOrderId"] = crr.CancelOrigClientOrderID
+	}
+	if crr.CancelNewClientOrderID != "" {
+		params["cancelNewClientOrderId"] = crr.CancelNewClientOrderID
+	}
+	if crr.CancelRestrictions != "" {
+		params["cancelRestrictions"] = string(crr.CancelRestrictions)
+	}
+	if crr.RecvWindow != 0 {
+		params["recvWindow"] = strconv.FormatInt(recvWindow(crr.RecvWindow), 10)
+	}
+
+	res, err := as.request("POST", "api/v3/order/cancelReplace", params, true, true)
+	if err != nil {
+		return nil, err
+	}
+	textRes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read response from order/cancelReplace")
+	}
+	defer res.Body.Close()
+
+	type rawCancelReplace struct {
+		CancelResult   string `json:"cancelResult"`
+		NewOrderResult string `json:"newOrderResult"`
+		CancelResponse *struct {
+			Error
+			Symbol            string     `json:"symbol"`
+			OrigClientOrderID string     `json:"origClientOrderId"`
+			OrderID           lenientInt `json:"orderId"`
+			ClientOrderID     string     `json:"clientOrderId"`
+		} `json:"cancelResponse"`
+		NewOrderResponse *struct {
+			Error
+			Symbol        string     `json:"symbol"`
+			OrderID       lenientInt `json:"orderId"`
+			ClientOrderID string     `json:"clientOrderId"`
+			TransactTime  int64      `json:"transactTime"`
+		} `json:"newOrderResponse"`
+	}
+	var rawResult rawCancelReplace
+	var apiErr error
+	if res.StatusCode != 200 {
+		// partial failure comes as error with result in data
+		rawError := struct {
+			Error
+			Data *rawCancelReplace `json:"data"`
+		}{}
+		if err := json.Unmarshal(textRes, &rawError); err != nil || rawError.Data == nil {
+			return nil, as.handleError(textRes)
+		}
+		apiErr = as.handleError(textRes)
+		rawResult = *rawError.Data
+	} else if err := json.Unmarshal(textRes, &rawResult); err != nil {
+		return nil, errors.Wrap(err, "rawCancelReplace unmarshal failed")
+	}
+
+	crres := &CancelReplaceResult{
+		CancelResult:   rawResult.CancelResult,
+		NewOrderResult: rawResult.NewOrderResult,
+	}
+	if rc := rawResult.CancelResponse; rc != nil {
+		if rc.Code != 0 {
+			crres.CancelError = &Error{Code: rc.Code, Message: rc.Message}
+		} else {
+			crres.CancelResponse = &CanceledOrder{
+				Symbol:            rc.Symbol,
+				OrigClientOrderID: rc.OrigClientOrderID,
+				OrderID:           int64(rc.OrderID),
+				ClientOrderID:     rc.ClientOrderID,
+			}
+		}
+	}
+	if rn := rawResult.NewOrderResponse; rn != nil {
+		if rn.Code != 0 {
+			crres.NewOrderError = &Error{Code: rn.Code, Message: rn.Message}
+		} else {
+			crres.NewOrderResponse = &ProcessedOrder{
+				Symbol:        rn.Symbol,
+				OrderID:       int64(rn.OrderID),
+				ClientOrderID: rn.ClientOrderID,
+				TransactTime:  timeFromUnixMillis(rn.TransactTime),
+			}
+		}
+	}
+	return crres, apiErr
+}
+
 func (as *apiService) OpenOrders(oor OpenOrdersRequest) ([]*ExecutedOrder, error) {
 	params := make(map[string]string)
-	params["symbol"] = oor.Symbol
+	params["symbol"] = NormalizeSymbol(oor.Symbol)
 	params["timestamp"] = strconv.FormatInt(unixMillis(oor.Timestamp), 10)
 	if oor.RecvWindow != 0 {
 		params["recvWindow"] = strconv.FormatInt(recvWindow(oor.RecvWindow), 10)
@@ -246,11 +479,17 @@ func (as *apiService) OpenOrders(oor OpenOrdersRequest) ([]*ExecutedOrder, error
 
 func (as *apiService) AllOrders(aor AllOrdersRequest) ([]*ExecutedOrder, error) {
 	params := make(map[string]string)
-	params["symbol"] = aor.Symbol
+	params["symbol"] = NormalizeSymbol(aor.Symbol)
 	params["timestamp"] = strconv.FormatInt(unixMillis(aor.Timestamp), 10)
 	if aor.OrderID != 0 {
 		params["orderId"] = strconv.FormatInt(aor.OrderID, 10)
 	}
+	if !aor.StartTime.IsZero() {
+		params["startTime"] = strconv.FormatInt(unixMillis(aor.StartTime), 10)
+	}
+	if !aor.EndTime.IsZero() {
+		params["endTime"] = strconv.FormatInt(unixMillis(aor.EndTime), 10)
+	}
 	if aor.Limit != 0 {
 		params["limit"] = strconv.Itoa(aor.Limit)
 	}
@@ -290,13 +529,7 @@ func (as *apiService) AllOrders(aor AllOrdersRequest) ([]*ExecutedOrder, error)
 }
 
 func (as *apiService) Account(ar AccountRequest) (*Account, error) {
-	params := make(map[string]string)
-	params["timestamp"] = strconv.FormatInt(unixMillis(ar.Timestamp), 10)
-	if ar.RecvWindow != 0 {
-		params["recvWindow"] = strconv.FormatInt(recvWindow(ar.RecvWindow), 10)
-	}
-
-	res, err := as.request("GET", "api/v3/account", params, true, true)
+	res, err := as.request("GET", "api/v3/account", accountParams(ar), true, true)
 	if err != nil {
 		return nil, err
 	}
@@ -309,9 +542,24 @@ func (as *apiService) Account(ar AccountRequest) (*Account, error) {
 	if res.StatusCode != 200 {
 		return nil, as.handleError(textRes)
 	}
+	return accountFromJSON(textRes)
+}
+
+func accountParams(ar AccountRequest) map[string]string {
+	params := make(map[string]string)
+	params["timestamp"] = strconv.FormatInt(unixMillis(ar.Timestamp), 10)
+	if ar.RecvWindow != 0 {
+		params["recvWindow"] = strconv.FormatInt(recvWindow(ar.RecvWindow), 10)
+	}
+	if ar.OmitZeroBalances {
+		params["omitZeroBalances"] = "true"
+	}
+	return params
+}
 
+func accountFromJSON(textRes []byte) (*Account, error) {
 	rawAccount := struct {
-		MakerCommision   int64 `json:"makerCommision"`
+		MakerCommision   int64 `json:"makerCommission"`
 		TakerCommission  int64 `json:"takerCommission"`
 		BuyerCommission  int64 `json:"buyerCommission"`
 		SellerCommission int64 `json:"sellerCommission"`
@@ -347,9 +595,11 @@ func (as *apiService) Account(ar AccountRequest) (*Account, error) {
 			return nil, err
 		}
 		acc.Balances = append(acc.Balances, &Balance{
-			Asset:  b.Asset,
-			Free:   f,
-			Locked: l,
+			Asset:     b.Asset,
+			Free:      f,
+			Locked:    l,
+			RawFree:   b.Free,
+			RawLocked: b.Locked,
 		})
 	}
 
@@ -358,13 +608,22 @@ func (as *apiService) Account(ar AccountRequest) (*Account, error) {
 
 func (as *apiService) MyTrades(mtr MyTradesRequest) ([]*MyTrade, error) {
 	params := make(map[string]string)
-	params["symbol"] = mtr.Symbol
+	params["symbol"] = NormalizeSymbol(mtr.Symbol)
 	params["timestamp"] = strconv.FormatInt(unixMillis(mtr.Timestamp), 10)
 	if mtr.RecvWindow != 0 {
 		params["recvWindow"] = strconv.FormatInt(recvWindow(mtr.RecvWindow), 10)
 	}
+	if mtr.OrderID != 0 {
+		params["orderId"] = strconv.FormatInt(mtr.OrderID, 10)
+	}
 	if mtr.FromID != 0 {
-		params["orderId"] = strconv.FormatInt(mtr.FromID, 10)
+		params["fromId"] = strconv.FormatInt(mtr.FromID, 10)
+	}
+	if !mtr.StartTime.IsZero() {
+		params["startTime"] = strconv.FormatInt(unixMillis(mtr.StartTime), 10)
+	}
+	if !mtr.EndTime.IsZero() {
+		params["endTime"] = strconv.FormatInt(unixMillis(mtr.EndTime), 10)
 	}
 	if mtr.Limit != 0 {
 		params["limit"] = strconv.Itoa(mtr.Limit)
@@ -427,6 +686,9 @@ func (as *apiService) MyTrades(mtr MyTradesRequest) ([]*MyTrade, error) {
 			IsBuyer:         rt.IsBuyer,
 			IsMaker:         rt.IsMaker,
 			IsBestMatch:     rt.IsBestMatch,
+			RawPrice:        rt.Price,
+			RawQty:          rt.Qty,
+			RawCommission:   rt.Commission,
 		})
 	}
 	return tc, nil
@@ -434,7 +696,7 @@ func (as *apiService) MyTrades(mtr MyTradesRequest) ([]*MyTrade, error) {
 
 func (as *apiService) Withdraw(wr WithdrawRequest) (*WithdrawResult, error) {
 	params := make(map[string]string)
-	params["asset"] = wr.Asset
+	params["coin"] = wr.Asset
 	params["address"] = wr.Address
 	params["amount"] = strconv.FormatFloat(wr.Amount, 'f', 10, 64)
 	params["timestamp"] = strconv.FormatInt(unixMillis(wr.Timestamp), 10)
@@ -445,7 +707,7 @@ func (as *apiService) Withdraw(wr WithdrawRequest) (*WithdrawResult, error) {
 		params["name"] = wr.Name
 	}
 
-	res, err := as.request("POST", "wapi/v1/withdraw.html", params, true, true)
+	res, err := as.request("POST", "sapi/v1/capital/withdraw/apply", params, true, true)
 	if err != nil {
 		return nil, err
 	}
@@ -460,44 +722,25 @@ func (as *apiService) Withdraw(wr WithdrawRequest) (*WithdrawResult, error) {
 	}
 
 	rawResult := struct {
-		Msg     string `json:"msg"`
-		Success bool   `json:"success"`
+		ID string `json:"id"`
 	}{}
 	if err := json.Unmarshal(textRes, &rawResult); err != nil {
-		return nil, errors.Wrap(err, "rawTrades unmarshal failed")
+		return nil, errors.Wrap(err, "rawResult unmarshal failed")
 	}
 
 	return &WithdrawResult{
-		Msg:     rawResult.Msg,
-		Success: rawResult.Success,
+		ID:      rawResult.ID,
+		Success: true,
 	}, nil
 }
 func (as *apiService) DepositHistory(hr HistoryRequest) ([]*Deposit, error) {
-	params := make(map[string]string)
-	params["timestamp"] = strconv.FormatInt(unixMillis(hr.Timestamp), 10)
-	if hr.Asset != "" {
-		params["asset"] = hr.Asset
-	}
-	if hr.Status != nil {
-		params["status"] = strconv.Itoa(*hr.Status)
-	}
-	if !hr.StartTime.IsZero() {
-		params["startTime"] = strconv.FormatInt(unixMillis(hr.StartTime), 10)
-	}
-	if !hr.EndTime.IsZero() {
-		params["startTime"] = strconv.FormatInt(unixMillis(hr.EndTime), 10)
-	}
-	if hr.RecvWindow != 0 {
-		params["recvWindow"] = strconv.FormatInt(recvWindow(hr.RecvWindow), 10)
-	}
-
-	res, err := as.request("POST", "wapi/v1/getDepositHistory.html", params, true, true)
+	res, err := as.request("GET", "sapi/v1/capital/deposit/hisrec", historyParams(hr), true, true)
 	if err != nil {
 		return nil, err
 	}
 	textRes, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return nil, errors.Wrap(err, "unable to read response from depositHistory.post")
+		return nil, errors.Wrap(err, "unable to read response from depositHistory.get")
 	}
 	defer res.Body.Close()
 
@@ -505,61 +748,48 @@ func (as *apiService) DepositHistory(hr HistoryRequest) ([]*Deposit, error) {
 		return nil, as.handleError(textRes)
 	}
 
-	rawDepositHistory := struct {
-		DepositList []struct {
-			InsertTime float64 `json:"insertTime"`
-			Amount     float64 `json:"amount"`
-			Asset      string  `json:"asset"`
-			Status     int     `json:"status"`
-		}
-		Success bool `json:"success"`
+	rawDeposits := []struct {
+		ID         string  `json:"id"`
+		InsertTime int64   `json:"insertTime"`
+		Amount     float64 `json:"amount,string"`
+		Coin       string  `json:"coin"`
+		Network    string  `json:"network"`
+		Address    string  `json:"address"`
+		TxID       string  `json:"txId"`
+		Status     int     `json:"status"`
 	}{}
-	if err := json.Unmarshal(textRes, &rawDepositHistory); err != nil {
-		return nil, errors.Wrap(err, "rawDepositHistory unmarshal failed")
+	if err := json.Unmarshal(textRes, &rawDeposits); err != nil {
+		return nil, errors.Wrap(err, "rawDeposits unmarshal failed")
 	}
 
 	var dc []*Deposit
-	for _, d := range rawDepositHistory.DepositList {
-		t, err := timeFromUnixTimestampFloat(d.InsertTime)
-		if err != nil {
-			return nil, err
-		}
+	for _, d := range rawDeposits {
 		dc = append(dc, &Deposit{
-			InsertTime: t,
+			ID:         d.ID,
+			InsertTime: timeFromUnixMillis(d.InsertTime),
 			Amount:     d.Amount,
-			Asset:      d.Asset,
+			Asset:      d.Coin,
+			Network:    d.Network,
+			Address:    d.Address,
+			TxID:       d.TxID,
 			Status:     d.Status,
 		})
 	}
 
 	return dc, nil
 }
-func (as *apiService) WithdrawHistory(hr HistoryRequest) ([]*Withdrawal, error) {
-	params := make(map[string]string)
-	params["timestamp"] = strconv.FormatInt(unixMillis(hr.Timestamp), 10)
-	if hr.Asset != "" {
-		params["asset"] = hr.Asset
-	}
-	if hr.Status != nil {
-		params["status"] = strconv.Itoa(*hr.Status)
-	}
-	if !hr.StartTime.IsZero() {
-		params["startTime"] = strconv.FormatInt(unixMillis(hr.StartTime), 10)
-	}
-	if !hr.EndTime.IsZero() {
-		params["startTime"] = strconv.FormatInt(unixMillis(hr.EndTime), 10)
-	}
-	if hr.RecvWindow != 0 {
-		params["recvWindow"] = strconv.FormatInt(recvWindow(hr.RecvWindow), 10)
-	}
 
-	res, err := as.request("POST", "wapi/v1/getWithdrawHistory.html", params, true, true)
+// withdrawApplyTimeLayout is layout of UTC apply time of withdrawal.
+const withdrawApplyTimeLayout = "2006-01-02 15:04:05"
+
+func (as *apiService) WithdrawHistory(hr HistoryRequest) ([]*Withdrawal, error) {
+	res, err := as.request("GET", "sapi/v1/capital/withdraw/history", historyParams(hr), true, true)
 	if err != nil {
 		return nil, err
 	}
 	textRes, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return nil, errors.Wrap(err, "unable to read response from withdrawHistory.post")
+		return nil, errors.Wrap(err, "unable to read response from withdrawHistory.get")
 	}
 	defer res.Body.Close()
 
@@ -567,40 +797,70 @@ func (as *apiService) WithdrawHistory(hr HistoryRequest) ([]*Withdrawal, error)
 		return nil, as.handleError(textRes)
 	}
 
-	rawWithdrawHistory := struct {
-		WithdrawList []struct {
-			Amount    float64 `json:"amount"`
-			Address   string  `json:"address"`
-			TxID      string  `json:"txId"`
-			Asset     string  `json:"asset"`
-			ApplyTime float64 `json:"insertTime"`
-			Status    int     `json:"status"`
-		}
-		Success bool `json:"success"`
+	rawWithdrawals := []struct {
+		ID             string  `json:"id"`
+		Amount         float64 `json:"amount,string"`
+		TransactionFee float64 `json:"transactionFee,string"`
+		Address        string  `json:"address"`
+		TxID           string  `json:"txId"`
+		Coin           string  `json:"coin"`
+		Network        string  `json:"network"`
+		ApplyTime      string  `json:"applyTime"`
+		Status         int     `json:"status"`
 	}{}
-	if err := json.Unmarshal(textRes, &rawWithdrawHistory); err != nil {
-		return nil, errors.Wrap(err, "rawWithdrawHistory unmarshal failed")
+	if err := json.Unmarshal(textRes, &rawWithdrawals); err != nil {
+		return nil, errors.Wrap(err, "rawWithdrawals unmarshal failed")
 	}
 
 	var wc []*Withdrawal
-	for _, w := range rawWithdrawHistory.WithdrawList {
-		t, err := timeFromUnixTimestampFloat(w.ApplyTime)
+	for _, w := range rawWithdrawals {
+		t, err := time.Parse(withdrawApplyTimeLayout, w.ApplyTime)
 		if err != nil {
-			return nil, err
+			return nil, errors.Wrap(err, "cannot parse Withdrawal.ApplyTime")
 		}
 		wc = append(wc, &Withdrawal{
-			Amount:    w.Amount,
-			Address:   w.Address,
-			TxID:      w.TxID,
-			Asset:     w.Asset,
-			ApplyTime: t,
-			Status:    w.Status,
+			ID:             w.ID,
+			Amount:         w.Amount,
+			TransactionFee: w.TransactionFee,
+			Address:        w.Address,
+			TxID:           w.TxID,
+			Asset:          w.Coin,
+			Network:        w.Network,
+			ApplyTime:      t,
+			Status:         w.Status,
 		})
 	}
 
 	return wc, nil
 }
 
+func historyParams(hr HistoryRequest) map[string]string {
+	params := make(map[string]string)
+	params["timestamp"] = strconv.FormatInt(unixMillis(hr.Timestamp), 10)
+	if hr.Asset != "" {
+		params["coin"] = hr.Asset
+	}
+	if hr.Status != nil {
+		params["status"] = strconv.Itoa(*hr.Status)
+	}
+	if !hr.StartTime.IsZero() {
+		params["startTime"] = strconv.FormatInt(unixMillis(hr.StartTime), 10)
+	}
+	if !hr.EndTime.IsZero() {
+		params["endTime"] = strconv.FormatInt(unixMillis(hr.EndTime), 10)
+	}
+	if hr.Limit != 0 {
+		params["limit"] = strconv.Itoa(hr.Limit)
+	}
+	if hr.Offset != 0 {
+		params["offset"] = strconv.Itoa(hr.Offset)
+	}
+	if hr.RecvWindow != 0 {
+		params["recvWindow"] = strconv.FormatInt(recvWindow(hr.RecvWindow), 10)
+	}
+	return params
+}
+
 func executedOrderFromRaw(reo *rawExecutedOrder) (*ExecutedOrder, error) {
 	price, err := strconv.ParseFloat(reo.Price, 64)
 	if err != nil {
@@ -614,6 +874,10 @@ func executedOrderFromRaw(reo *rawExecutedOrder) (*ExecutedOrder, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot parse Order.ExecutedQty")
 	}
+	cumQuoteQty, err := strconv.ParseFloat(reo.CumQuoteQty, 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse Order.CumQuoteQty")
+	}
 	stopPrice, err := strconv.ParseFloat(reo.StopPrice, 64)
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot parse Order.StopPrice")
@@ -629,11 +893,12 @@ func executedOrderFromRaw(reo *rawExecutedOrder) (*ExecutedOrder, error) {
 
 	return &ExecutedOrder{
 		Symbol:        reo.Symbol,
-		OrderID:       reo.OrderID,
+		OrderID:       int(reo.OrderID),
 		ClientOrderID: reo.ClientOrderID,
 		Price:         price,
 		OrigQty:       origQty,
 		ExecutedQty:   execQty,
+		CumQuoteQty:   cumQuoteQty,
 		Status:        OrderStatus(reo.Status),
 		TimeInForce:   TimeInForce(reo.TimeInForce),
 		Type:          OrderType(reo.Type),
@@ -641,5 +906,7 @@ func executedOrderFromRaw(reo *rawExecutedOrder) (*ExecutedOrder, error) {
 		StopPrice:     stopPrice,
 		IcebergQty:    icebergQty,
 		Time:          t,
+		UpdateTime:    timeFromUnixMillis(reo.UpdateTime),
+		IsWorking:     reo.IsWorking,
 	}, nil
 }
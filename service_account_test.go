@@ -0,0 +1,540 @@
+package binance
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNewOrderParams(t *testing.T) {
+	tests := []struct {
+		name       string
+		or         NewOrderRequest
+		wantParams map[string]string
+		wantErr    bool
+	}{
+		{
+			name: "limit maker",
+			or: NewOrderRequest{
+				Symbol:   "bnbbtc",
+				Side:     SideSell,
+				Type:     TypeLimitMaker,
+				Quantity: 2,
+				Price:    0.0025,
+			},
+			wantParams: map[string]string{
+				"symbol":   "BNBBTC",
+				"side":     "SELL",
+				"type":     "LIMIT_MAKER",
+				"quantity": "2.000000",
+				"price":    "0.0025",
+			},
+		},
+		{
+			name: "limit",
+			or: NewOrderRequest{
+				Symbol:      "BNBBTC",
+				Side:        SideBuy,
+				Type:        TypeLimit,
+				TimeInForce: GTC,
+				Quantity:    1.5,
+				Price:       0.002,
+			},
+			wantParams: map[string]string{
+				"symbol":      "BNBBTC",
+				"side":        "BUY",
+				"type":        "LIMIT",
+				"timeInForce": "GTC",
+				"quantity":    "1.500000",
+				"price":       "0.002",
+			},
+		},
+		{
+			name: "limit maker with time in force",
+			or: NewOrderRequest{
+				Symbol:      "BNBBTC",
+				Side:        SideSell,
+				Type:        TypeLimitMaker,
+				TimeInForce: GTC,
+				Quantity:    2,
+				Price:       0.0025,
+			},
+			wantErr: true,
+		},
+		{
+			name: "limit maker without price",
+			or: NewOrderRequest{
+				Symbol:   "BNBBTC",
+				Side:     SideSell,
+				Type:     TypeLimitMaker,
+				Quantity: 2,
+			},
+			wantErr: true,
+		},
+	}
+	as := newTestServiceOf(t, "http://localhost", WithOrderValidation())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.or.Timestamp = time.Unix(1600000000, 0)
+			params, err := as.newOrderParams(tt.or)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("no validation error, params %v", params)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.wantParams["timestamp"] = "1600000000000"
+			if len(params) != len(tt.wantParams) {
+				t.Errorf("params = %v, want %v", params, tt.wantParams)
+			}
+			for k, want := range tt.wantParams {
+				if got, ok := params[k]; !ok || got != want {
+					t.Errorf("param %s = %q, want %q", k, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewOrderLimitMaker(t *testing.T) {
+	as := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.Method != "POST" || r.URL.Path != "/api/v3/order" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if typ := q.Get("type"); typ != "LIMIT_MAKER" {
+			t.Errorf("type = %q, want LIMIT_MAKER", typ)
+		}
+		if _, ok := q["timeInForce"]; ok {
+			t.Errorf("timeInForce sent for LIMIT_MAKER order: %s", r.URL.RawQuery)
+		}
+		if q.Get("signature") == "" {
+			t.Error("order not signed")
+		}
+		fmt.Fprint(w, `{"symbol":"BNBBTC","orderId":28,"clientOrderId":"6gCrw2kRUAF9CvJDGP16IP",`+
+			`"transactTime":1507725176595}`)
+	}, WithOrderValidation())
+
+	po, err := as.NewOrder(NewOrderRequest{
+		Symbol:    "BNBBTC",
+		Side:      SideSell,
+		Type:      TypeLimitMaker,
+		Quantity:  2,
+		Price:     0.0025,
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if po.OrderID != 28 {
+		t.Errorf("unexpected order %+v", po)
+	}
+}
+
+func TestNewOrderRecoveryCircuitOpen(t *testing.T) {
+	as := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/time" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, `{"code":-1001,"msg":"Internal error; unable to process your request."}`)
+	},
+		WithOrderRecovery(),
+		WithCircuitBreaker(1, time.Minute),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, Backoff: 2 * time.Second}),
+	)
+	// trip the breaker
+	if _, err := as.Time(); err == nil {
+		t.Fatal("time request didn't fail")
+	}
+
+	start := time.Now()
+	_, err := as.NewOrder(NewOrderRequest{
+		Symbol:           "BNBBTC",
+		Side:             SideBuy,
+		Type:             TypeLimit,
+		TimeInForce:      GTC,
+		Quantity:         1,
+		Price:            0.002,
+		NewClientOrderID: "recovered",
+		Timestamp:        time.Now(),
+	})
+	if err != ErrCircuitOpen {
+		t.Errorf("err = %v, want ErrCircuitOpen", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("order failed after %v, want immediate failure", d)
+	}
+}
+
+func TestAccountFixtures(t *testing.T) {
+	now := time.Now()
+	openOrder := &ExecutedOrder{
+		Symbol:        "LTCBTC",
+		OrderID:       1,
+		ClientOrderID: "myOrder1",
+		Price:         0.1,
+		OrigQty:       1,
+		Status:        StatusNew,
+		TimeInForce:   GTC,
+		Type:          TypeLimit,
+		Side:          SideBuy,
+		Time:          timeFromUnixMillis(1499827319559),
+		UpdateTime:    timeFromUnixMillis(1499827319559),
+		IsWorking:     true,
+	}
+	orderLists := []*ListStatus{
+		{
+			Symbol:            "LTCBTC",
+			OrderListID:       29,
+			ContingencyType:   "OCO",
+			ListStatusType:    "EXEC_STARTED",
+			ListOrderStatus:   "EXECUTING",
+			ListClientOrderID: "amEEAXryFzFwYF1FeRpUoZ",
+			TransactionTime:   timeFromUnixMillis(1565245913483),
+			Orders: []*ListStatusOrder{
+				{Symbol: "LTCBTC", OrderID: 4, ClientOrderID: "oD7aesZqjEGlZrbtRpy5zB"},
+				{Symbol: "LTCBTC", OrderID: 5, ClientOrderID: "Jr1h6xirOxgeJOUuYQS7V3"},
+			},
+		},
+	}
+	stream := &Stream{ListenKey: "pqia91ma19a5s61cv6a81va65sdf19v8a65a1a5s61cv6a81va65sdf19v8a65a1"}
+	testRESTFixtures(t, []restFixture{
+		{
+			name:    "NewOrder",
+			method:  "POST",
+			path:    "/api/v3/order",
+			fixture: "order_new.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.NewOrder(NewOrderRequest{
+					Symbol:    "BTCUSDT",
+					Side:      SideBuy,
+					Type:      TypeMarket,
+					Quantity:  1,
+					Timestamp: now,
+				})
+			},
+			want: &ProcessedOrder{
+				Symbol:        "BTCUSDT",
+				OrderID:       28,
+				ClientOrderID: "6gCrw2kRUAF9CvJDGP16IP",
+				TransactTime:  timeFromUnixMillis(1507725176595),
+			},
+		},
+		{
+			name:    "NewOrder error",
+			method:  "POST",
+			path:    "/api/v3/order",
+			fixture: "error_invalid_symbol.json",
+			status:  http.StatusBadRequest,
+			call: func(as *apiService) (interface{}, error) {
+				return as.NewOrder(NewOrderRequest{
+					Symbol:    "XXX",
+					Side:      SideBuy,
+					Type:      TypeMarket,
+					Quantity:  1,
+					Timestamp: now,
+				})
+			},
+			wantErr: &Error{Code: -1121, Message: "Invalid symbol."},
+		},
+		{
+			name:    "NewOrderTestResult",
+			method:  "POST",
+			path:    "/api/v3/order/test",
+			fixture: "order_test_commission.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.NewOrderTestResult(NewOrderRequest{
+					Symbol:                 "BTCUSDT",
+					Side:                   SideBuy,
+					Type:                   TypeMarket,
+					Quantity:               1,
+					Timestamp:              now,
+					ComputeCommissionRates: true,
+				})
+			},
+			want: &TestOrderResult{
+				StandardCommissionForOrder: CommissionRates{Maker: 0.00000112, Taker: 0.00000114},
+				TaxCommissionForOrder:      CommissionRates{Maker: 0.00000112, Taker: 0.00000114},
+				Discount: CommissionDiscount{
+					EnabledForAccount: true,
+					EnabledForSymbol:  true,
+					DiscountAsset:     "BNB",
+					Discount:          0.25,
+				},
+			},
+		},
+		{
+			name:    "QueryOrder",
+			method:  "GET",
+			path:    "/api/v3/order",
+			fixture: "order_query.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.QueryOrder(QueryOrderRequest{Symbol: "LTCBTC", OrderID: 1, Timestamp: now})
+			},
+			want: openOrder,
+		},
+		{
+			name:    "CancelOrder",
+			method:  "DELETE",
+			path:    "/api/v3/order",
+			fixture: "order_cancel.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.CancelOrder(CancelOrderRequest{Symbol: "LTCBTC", OrigClientOrderID: "myOrder1", Timestamp: now})
+			},
+			want: &CanceledOrder{
+				Symbol:            "LTCBTC",
+				OrigClientOrderID: "myOrder1",
+				OrderID:           4,
+				ClientOrderID:     "cancelMyOrder1",
+			},
+		},
+		{
+			name:    "CancelReplaceOrder",
+			method:  "POST",
+			path:    "/api/v3/order/cancelReplace",
+			fixture: "order_cancel_replace.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.CancelReplaceOrder(CancelReplaceRequest{
+					NewOrderRequest: NewOrderRequest{
+						Symbol:      "BTCUSDT",
+						Side:        SideBuy,
+						Type:        TypeLimit,
+						TimeInForce: GTC,
+						Quantity:    0.04,
+						Price:       0.02,
+						Timestamp:   now,
+					},
+					CancelReplaceMode: StopOnFailure,
+					CancelOrderID:     9,
+				})
+			},
+			want: &CancelReplaceResult{
+				CancelResult:   "SUCCESS",
+				NewOrderResult: "SUCCESS",
+				CancelResponse: &CanceledOrder{
+					Symbol:            "BTCUSDT",
+					OrigClientOrderID: "DnLo3vTAQcjha43lAZhZ0y",
+					OrderID:           9,
+					ClientOrderID:     "osxN3JXAtJvKvCqGeMWMVR",
+				},
+				NewOrderResponse: &ProcessedOrder{
+					Symbol:        "BTCUSDT",
+					OrderID:       10,
+					ClientOrderID: "wOceeeOzNORyLiQfw7jd8S",
+					TransactTime:  timeFromUnixMillis(1652928801803),
+				},
+			},
+		},
+		{
+			name:    "OpenOrders",
+			method:  "GET",
+			path:    "/api/v3/openOrders",
+			fixture: "open_orders.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.OpenOrders(OpenOrdersRequest{Symbol: "LTCBTC", Timestamp: now})
+			},
+			want: []*ExecutedOrder{openOrder},
+		},
+		{
+			name:    "AllOrders",
+			method:  "GET",
+			path:    "/api/v3/allOrders",
+			fixture: "all_orders.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.AllOrders(AllOrdersRequest{Symbol: "LTCBTC", Timestamp: now})
+			},
+			want: []*ExecutedOrder{
+				openOrder,
+				{
+					Symbol:        "LTCBTC",
+					OrderID:       2,
+					ClientOrderID: "myOrder2",
+					Price:         0.2,
+					OrigQty:       2,
+					ExecutedQty:   2,
+					CumQuoteQty:   0.4,
+					Status:        StatusFilled,
+					TimeInForce:   IOC,
+					Type:          TypeLimit,
+					Side:          SideSell,
+					Time:          timeFromUnixMillis(1499827320000),
+					UpdateTime:    timeFromUnixMillis(1499827321000),
+					IsWorking:     true,
+				},
+			},
+		},
+		{
+			name:    "OpenOCOOrders",
+			method:  "GET",
+			path:    "/api/v3/openOrderList",
+			fixture: "order_lists.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.OpenOCOOrders(OpenOCOOrdersRequest{Timestamp: now})
+			},
+			want: orderLists,
+		},
+		{
+			name:    "AllOCOOrders",
+			method:  "GET",
+			path:    "/api/v3/allOrderList",
+			fixture: "order_lists.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.AllOCOOrders(AllOCOOrdersRequest{Timestamp: now})
+			},
+			want: orderLists,
+		},
+		{
+			name:    "Account",
+			method:  "GET",
+			path:    "/api/v3/account",
+			fixture: "account.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.Account(AccountRequest{Timestamp: now})
+			},
+			want: &Account{
+				MakerCommision:  15,
+				TakerCommision:  15,
+				BuyerCommision:  0,
+				SellerCommision: 0,
+				CanTrade:        true,
+				CanWithdraw:     true,
+				CanDeposit:      true,
+				Balances: []*Balance{
+					{Asset: "BTC", Free: 4723846.89208129, Locked: 0, RawFree: "4723846.89208129", RawLocked: "0.00000000"},
+					{Asset: "LTC", Free: 4763368.68006011, Locked: 0.5, RawFree: "4763368.68006011", RawLocked: "0.50000000"},
+				},
+			},
+		},
+		{
+			name:    "MyTrades",
+			method:  "GET",
+			path:    "/api/v3/myTrades",
+			fixture: "my_trades.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.MyTrades(MyTradesRequest{Symbol: "BNBBTC", Timestamp: now})
+			},
+			want: []*MyTrade{
+				{
+					ID:              28457,
+					Price:           4.000001,
+					Qty:             12,
+					Commission:      10.1,
+					CommissionAsset: "BNB",
+					Time:            timeFromUnixMillis(1499865549590),
+					IsBuyer:         true,
+					IsMaker:         false,
+					IsBestMatch:     true,
+					RawPrice:        "4.00000100",
+					RawQty:          "12.00000000",
+					RawCommission:   "10.10000000",
+				},
+			},
+		},
+		{
+			name:    "Withdraw",
+			method:  "POST",
+			path:    "/sapi/v1/capital/withdraw/apply",
+			fixture: "withdraw.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.Withdraw(WithdrawRequest{Asset: "BNB", Address: "bnb1", Amount: 1, Timestamp: now})
+			},
+			want: &WithdrawResult{ID: "7213fea8e94b4a5593d507237e5a555b", Success: true},
+		},
+		{
+			name:    "DepositHistory",
+			method:  "GET",
+			path:    "/sapi/v1/capital/deposit/hisrec",
+			fixture: "deposit_history.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.DepositHistory(HistoryRequest{Timestamp: now})
+			},
+			want: []*Deposit{
+				{
+					ID:         "769800519366885376",
+					InsertTime: timeFromUnixMillis(1661493146000),
+					Amount:     0.001,
+					Asset:      "BNB",
+					Network:    "BNB",
+					Address:    "bnb136ns6lfw4zs5hg4n85vdthaad7hq5m4gtkgf23",
+					TxID:       "98A3EA560C6B3336D348B6C83F0F95ECE4F1F5919E94BD006E5BF3BF264FACFC",
+					Status:     1,
+				},
+			},
+		},
+		{
+			name:    "WithdrawHistory",
+			method:  "GET",
+			path:    "/sapi/v1/capital/withdraw/history",
+			fixture: "withdraw_history.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.WithdrawHistory(HistoryRequest{Timestamp: now})
+			},
+			want: []*Withdrawal{
+				{
+					ID:             "b6ae22b3aa844210a7041aee7589627c",
+					Amount:         8.91,
+					TransactionFee: 0.004,
+					Address:        "0x94df8b352de7f46f64b01d3666bf6e936e44ce60",
+					TxID:           "0xb5ef8c13b968a406cc62a93a8bd80f9e9a906ef1b3fcf20a2e48573c17659268",
+					Asset:          "USDT",
+					Network:        "ETH",
+					ApplyTime:      time.Date(2019, 10, 12, 11, 12, 2, 0, time.UTC),
+					Status:         6,
+				},
+			},
+		},
+		{
+			name:    "StartUserDataStream",
+			method:  "POST",
+			path:    "/api/v1/userDataStream",
+			fixture: "user_data_stream.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.StartUserDataStream()
+			},
+			want: stream,
+		},
+		{
+			name:    "StartMarginUserDataStream",
+			method:  "POST",
+			path:    "/sapi/v1/userDataStream",
+			fixture: "user_data_stream.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.StartMarginUserDataStream()
+			},
+			want: &Stream{ListenKey: stream.ListenKey, Margin: true},
+		},
+		{
+			name:    "StartIsolatedMarginUserDataStream",
+			method:  "POST",
+			path:    "/sapi/v1/userDataStream/isolated",
+			fixture: "user_data_stream.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.StartIsolatedMarginUserDataStream("bnbbtc")
+			},
+			want: &Stream{ListenKey: stream.ListenKey, Symbol: "BNBBTC"},
+		},
+		{
+			name:    "KeepAliveUserDataStream",
+			method:  "PUT",
+			path:    "/api/v1/userDataStream",
+			fixture: "empty.json",
+			call: func(as *apiService) (interface{}, error) {
+				return nil, as.KeepAliveUserDataStream(stream)
+			},
+		},
+		{
+			name:    "CloseUserDataStream",
+			method:  "DELETE",
+			path:    "/api/v1/userDataStream",
+			fixture: "empty.json",
+			call: func(as *apiService) (interface{}, error) {
+				return nil, as.CloseUserDataStream(stream)
+			},
+		},
+	})
+}
@@ -1,13 +1,20 @@
 package binance
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
+	"github.com/gorilla/websocket"
 	"github.com/pkg/errors"
 )
 
@@ -22,18 +29,26 @@ type Service interface {
 	AggTrades(atr AggTradesRequest) ([]*AggTrade, error)
 	HistoricalTrades(htr HistoricalTradesRequest) ([]*HistoricalTrades, error)
 	ExchangeInfo() (*ExchangeInfo, error)
+	CachedExchangeInfo() (*ExchangeInfo, error)
+	RefreshExchangeInfo() (*ExchangeInfo, error)
 
 	Klines(kr KlinesRequest) ([]*Kline, error)
+	UIKlines(kr KlinesRequest) ([]*Kline, error)
 	Ticker24(tr TickerRequest) (*Ticker24, error)
+	Tickers24(tr TickerRequest) ([]*Ticker24, error)
 	TickerAllPrices() ([]*PriceTicker, error)
 	TickerAllBooks() ([]*BookTicker, error)
 
 	NewOrder(or NewOrderRequest) (*ProcessedOrder, error)
 	NewOrderTest(or NewOrderRequest) error
+	NewOrderTestResult(or NewOrderRequest) (*TestOrderResult, error)
 	QueryOrder(qor QueryOrderRequest) (*ExecutedOrder, error)
 	CancelOrder(cor CancelOrderRequest) (*CanceledOrder, error)
+	CancelReplaceOrder(crr CancelReplaceRequest) (*CancelReplaceResult, error)
 	OpenOrders(oor OpenOrdersRequest) ([]*ExecutedOrder, error)
 	AllOrders(aor AllOrdersRequest) ([]*ExecutedOrder, error)
+	OpenOCOOrders(oor OpenOCOOrdersRequest) ([]*ListStatus, error)
+	AllOCOOrders(aor AllOCOOrdersRequest) ([]*ListStatus, error)
 
 	Account(ar AccountRequest) (*Account, error)
 	MyTrades(mtr MyTradesRequest) ([]*MyTrade, error)
@@ -42,57 +57,232 @@ type Service interface {
 	WithdrawHistory(hr HistoryRequest) ([]*Withdrawal, error)
 
 	StartUserDataStream() (*Stream, error)
+	StartMarginUserDataStream() (*Stream, error)
+	StartIsolatedMarginUserDataStream(symbol string) (*Stream, error)
 	KeepAliveUserDataStream(s *Stream) error
 	CloseUserDataStream(s *Stream) error
 
 	DepthWebsocket(dwr DepthWebsocketRequest) (chan *DepthEvent, chan struct{}, error)
 	KlineWebsocket(kwr KlineWebsocketRequest) (chan *KlineEvent, chan struct{}, error)
+	KlinesWithLive(symbol string, interval Interval, lookback int) (chan *KlineEvent, chan struct{}, error)
 	AggTradeWebsocket(twr AggTradeWebsocketRequest) (chan *AggTradeEvent, chan struct{}, error)
 	TradeWebsocket(twr TradeWebsocketRequest) (chan *TradeEvent, chan struct{}, error)
 	UserDataWebsocket(udwr UserDataWebsocketRequest) (chan *AccountEvent, chan struct{}, error)
+	UserDataStream() (chan *AccountEvent, chan struct{}, error)
+	MarginUserDataStream() (chan *AccountEvent, chan struct{}, error)
+	IsolatedMarginUserDataStream(symbol string) (chan *AccountEvent, chan struct{}, error)
+	StreamErrors() <-chan error
+	Streams() []StreamStatus
+	CloseStream(done <-chan struct{})
+	Shutdown(ctx context.Context) error
+	NewStreamClient() (StreamClient, error)
+	NewWSAPIClient() (WSAPIClient, error)
+	OrderCount(interval string) int
+	CircuitState() CircuitState
+	WithCredentials(apiKey string, signer Signer) Service
+
+	FuturesExchangeInfo() (*ExchangeInfo, error)
+	FuturesKlines(kr KlinesRequest) ([]*Kline, error)
+	FuturesNewOrder(fnor FuturesNewOrderRequest) (*FuturesOrder, error)
+	FuturesAccount(ar AccountRequest) (*FuturesAccount, error)
+	FuturesPositionRisk(fprr FuturesPositionRiskRequest) ([]*FuturesPosition, error)
+	FundingRateHistory(frr FundingRateRequest) ([]*FundingRate, error)
+	MarkPriceWebsocket(mpwr MarkPriceWebsocketRequest) (chan *MarkPriceEvent, chan struct{}, error)
+
+	SubAccountList(salr SubAccountListRequest) ([]*SubAccount, error)
+	SubAccountTransfer(satr SubAccountTransferRequest) (*SubAccountTransferResult, error)
+	SubAccountAssets(saar SubAccountAssetsRequest) ([]*Balance, error)
+
+	LendingProductList(lplr LendingProductListRequest) ([]*LendingProduct, error)
+	LendingPurchase(lpr LendingPurchaseRequest) (*LendingPurchaseResult, error)
+	LendingRedeem(lrr LendingRedeemRequest) (*LendingRedeemResult, error)
+
+	AccountSnapshot(asr AccountSnapshotRequest) (*AccountSnapshot, error)
+	UniversalTransfer(utr UniversalTransferRequest) (*TransferResult, error)
+	FundingAssets(far FundingAssetsRequest) ([]*FundingAsset, error)
+	UserAssets(far FundingAssetsRequest) ([]*FundingAsset, error)
+	DustEstimate() (*DustEstimate, error)
 }
 
+// FuturesURL is base URL of USDT-M futures API.
+const FuturesURL = "https://fapi.binance.com"
+
+// apiService is configured by NewAPIService and its options only, fields
+// are read-only afterwards and state updated by responses is guarded by
+// its own locks.
 type apiService struct {
-	URL    string
-	APIKey string
-	Signer Signer
-	Logger log.Logger
-	Ctx    context.Context
+	URL        string
+	FuturesURL string
+	APIKey     string
+	Signer     Signer
+	Logger     log.Logger
+	Ctx        context.Context
+
+	// cancelStream cancels Ctx of copy made by streamService.
+	cancelStream context.CancelFunc
+
+	logLevel       level.Option
+	streamErrors   chan error
+	dialer         *websocket.Dialer
+	client         *http.Client
+	proxy          *url.URL
+	requestTimeout time.Duration
+	observer       Observer
+	responseHook   ResponseHook
+	debug          bool
+	retryPolicy    RetryPolicy
+	breaker        *circuitBreaker
+	exchangeInfo   *exchangeInfoCache
+	streams        *streamRegistry
+
+	wsReadBufferSize  int
+	wsWriteBufferSize int
+	wsCompression     bool
+	wsStaleTimeout    time.Duration
+	rawEvents         bool
+
+	clientOrderIDs      bool
+	clientOrderIDPrefix string
+	validateOrders      bool
+	orderRecovery       bool
+
+	// orderCounts are tracked per API key, while clientOrderIDSeq, breaker,
+	// exchangeInfo and streams are shared by copies made by WithCredentials.
+	orderCounts      *orderCounter
+	clientOrderIDSeq *uint64
+}
+
+// orderCounter stores order counts reported for single API key.
+type orderCounter struct {
+	mu     sync.Mutex
+	counts map[string]int
 }
 
+// DefaultRequestTimeout limits REST requests of default HTTP client, including
+// reading response body.
+const DefaultRequestTimeout = 30 * time.Second
+
 // NewAPIService creates instance of Service.
 //
 // If logger or ctx are not provided, NopLogger and Background context are used as default.
 // You can use context for one-time request cancel (e.g. when shutting down the app).
-func NewAPIService(url, apiKey string, signer Signer, logger log.Logger, ctx context.Context) Service {
+// Additional behaviour can be configured with options, e.g. WithLogLevel,
+// WithHTTPClient or WithRequestTimeout. Default HTTP client times out REST
+// requests after DefaultRequestTimeout, so stuck connections don't hang.
+//
+// Service is safe for concurrent use by multiple goroutines as long as
+// provided logger, signer and observer are, e.g. wrap writer of go-kit logger
+// with log.NewSyncWriter.
+func NewAPIService(url, apiKey string, signer Signer, logger log.Logger, ctx context.Context,
+	opts ...ServiceOption) Service {
 	if logger == nil {
 		logger = log.NewNopLogger()
 	}
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	return &apiService{
-		URL:    url,
-		APIKey: apiKey,
-		Signer: signer,
-		Logger: logger,
-		Ctx:    ctx,
+	as := &apiService{
+		URL:        url,
+		FuturesURL: FuturesURL,
+		APIKey:     apiKey,
+		Signer:     signer,
+		Logger:     logger,
+		Ctx:        ctx,
+
+		streamErrors:   make(chan error, streamErrorsBuffer),
+		dialer:         NewDialer(),
+		observer:       NopObserver{},
+		retryPolicy:    DefaultRetryPolicy,
+		requestTimeout: DefaultRequestTimeout,
+		exchangeInfo:   &exchangeInfoCache{ttl: DefaultExchangeInfoTTL},
+		streams:        newStreamRegistry(),
+
+		orderCounts:      &orderCounter{counts: make(map[string]int)},
+		clientOrderIDSeq: new(uint64),
+	}
+	for _, opt := range opts {
+		opt(as)
+	}
+	if as.proxy != nil {
+		if as.client == nil {
+			as.client = &http.Client{
+				Transport: &http.Transport{Proxy: http.ProxyURL(as.proxy)},
+				Timeout:   as.requestTimeout,
+			}
+		}
+		dialer := *as.dialer
+		dialer.Proxy = http.ProxyURL(as.proxy)
+		as.dialer = &dialer
+	}
+	if as.client == nil {
+		as.client = &http.Client{Timeout: as.requestTimeout}
 	}
+	if as.wsReadBufferSize != 0 || as.wsWriteBufferSize != 0 || as.wsCompression {
+		dialer := *as.dialer
+		if as.wsReadBufferSize != 0 {
+			dialer.ReadBufferSize = as.wsReadBufferSize
+		}
+		if as.wsWriteBufferSize != 0 {
+			dialer.WriteBufferSize = as.wsWriteBufferSize
+		}
+		if as.wsCompression {
+			dialer.EnableCompression = true
+		}
+		as.dialer = &dialer
+	}
+	if as.logLevel != nil {
+		as.Logger = level.NewFilter(as.Logger, as.logLevel)
+	}
+	return as
 }
 
+// requestFunc sends request to one of API hosts.
+type requestFunc func(method string, endpoint string, params map[string]string,
+	apiKey bool, sign bool) (*http.Response, error)
+
 func (as *apiService) request(method string, endpoint string, params map[string]string,
 	apiKey bool, sign bool) (*http.Response, error) {
-	transport := &http.Transport{}
-	client := &http.Client{
-		Transport: transport,
+	return as.requestURL(as.URL, method, endpoint, params, apiKey, sign)
+}
+
+func (as *apiService) futuresRequest(method string, endpoint string, params map[string]string,
+	apiKey bool, sign bool) (*http.Response, error) {
+	return as.requestURL(as.FuturesURL, method, endpoint, params, apiKey, sign)
+}
+
+// requestURL sends request, retrying it according to retry policy.
+func (as *apiService) requestURL(baseURL string, method string, endpoint string, params map[string]string,
+	apiKey bool, sign bool) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := as.sendRequest(baseURL, method, endpoint, params, apiKey, sign)
+		if attempt >= as.retryPolicy.MaxAttempts || as.Ctx.Err() != nil || err == ErrCircuitOpen ||
+			!as.retryPolicy.shouldRetry(method, resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		delay := as.retryPolicy.delay(attempt)
+		level.Info(as.Logger).Log("retry", endpoint, "attempt", attempt, "delay", delay, "err", err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-as.Ctx.Done():
+			timer.Stop()
+			return nil, as.Ctx.Err()
+		}
 	}
+}
 
-	url := fmt.Sprintf("%s/%s", as.URL, endpoint)
+func (as *apiService) sendRequest(baseURL string, method string, endpoint string, params map[string]string,
+	apiKey bool, sign bool) (*http.Response, error) {
+	url := fmt.Sprintf("%s/%s", baseURL, endpoint)
 	req, err := http.NewRequest(method, url, nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create request")
 	}
-	req.WithContext(as.Ctx)
+	req = req.WithContext(as.Ctx)
 
 	q := req.URL.Query()
 	for key, val := range params {
@@ -104,13 +294,119 @@ func (as *apiService) request(method string, endpoint string, params map[string]
 	if sign {
 		level.Debug(as.Logger).Log("queryString", q.Encode())
 		q.Add("signature", as.Signer.Sign([]byte(q.Encode())))
-		level.Debug(as.Logger).Log("signature", as.Signer.Sign([]byte(q.Encode())))
 	}
 	req.URL.RawQuery = q.Encode()
 
-	resp, err := client.Do(req)
+	if as.breaker != nil {
+		if err := as.breaker.allow(); err != nil {
+			return nil, err
+		}
+	}
+	as.observer.RequestStarted(method, endpoint)
+	start := time.Now()
+	resp, err := as.client.Do(req)
+	if as.breaker != nil && as.breaker.record(resp, err, as.Ctx.Err() != nil) {
+		level.Warn(as.Logger).Log("circuitBreaker", "opened", "endpoint", endpoint)
+	}
 	if err != nil {
+		as.observer.RequestFinished(method, endpoint, 0, time.Since(start), err)
 		return nil, err
 	}
+	as.observer.RequestFinished(method, endpoint, resp.StatusCode, time.Since(start), nil)
+	as.observeUsedWeight(resp.Header)
+	as.updateOrderCounts(resp.Header)
+
+	if as.debug {
+		if err := as.logExchange(req, resp); err != nil {
+			return nil, err
+		}
+	}
+	if as.responseHook != nil {
+		body, err := peekBody(resp)
+		if err != nil {
+			return nil, err
+		}
+		as.responseHook(method, endpoint, resp.StatusCode, body)
+	}
 	return resp, nil
 }
+
+// peekBody reads response body, leaving it readable by caller.
+func peekBody(resp *http.Response) ([]byte, error) {
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read response body")
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// logExchange logs request and raw response body, leaving response body
+// readable by caller. Signature and API key are redacted.
+func (as *apiService) logExchange(req *http.Request, resp *http.Response) error {
+	body, err := peekBody(resp)
+	if err != nil {
+		return err
+	}
+
+	u := *req.URL
+	q := u.Query()
+	if q.Get("signature") != "" {
+		q.Set("signature", redacted)
+	}
+	u.RawQuery = q.Encode()
+	apiKey := ""
+	if req.Header.Get("X-MBX-APIKEY") != "" {
+		apiKey = redacted
+	}
+	level.Debug(as.Logger).Log("method", req.Method, "url", u.String(), "apiKey", apiKey,
+		"status", resp.StatusCode, "response", string(body))
+	return nil
+}
+
+const redacted = "REDACTED"
+
+const orderCountHeaderPrefix = "X-Mbx-Order-Count-"
+
+// updateOrderCounts stores order counts reported by X-MBX-ORDER-COUNT-*
+// response headers, e.g. X-MBX-ORDER-COUNT-10S.
+func (as *apiService) updateOrderCounts(header http.Header) {
+	for key, values := range header {
+		if !strings.HasPrefix(key, orderCountHeaderPrefix) || len(values) == 0 {
+			continue
+		}
+		count, err := strconv.Atoi(values[0])
+		if err != nil {
+			level.Warn(as.Logger).Log("orderCountHeader", key, "err", err)
+			continue
+		}
+		interval := strings.ToUpper(strings.TrimPrefix(key, orderCountHeaderPrefix))
+		as.orderCounts.mu.Lock()
+		as.orderCounts.counts[interval] = count
+		as.orderCounts.mu.Unlock()
+	}
+}
+
+func (as *apiService) OrderCount(interval string) int {
+	as.orderCounts.mu.Lock()
+	defer as.orderCounts.mu.Unlock()
+	return as.orderCounts.counts[strings.ToUpper(interval)]
+}
+
+func (as *apiService) CircuitState() CircuitState {
+	if as.breaker == nil {
+		return CircuitClosed
+	}
+	return as.breaker.state()
+}
+
+// WithCredentials returns copy of service acting on behalf of another API
+// key, sharing configuration and connections with the original.
+func (as *apiService) WithCredentials(apiKey string, signer Signer) Service {
+	c := *as
+	c.APIKey = apiKey
+	c.Signer = signer
+	c.orderCounts = &orderCounter{counts: make(map[string]int)}
+	return &c
+}
@@ -0,0 +1,122 @@
+package binance
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingObserver counts requests and used weight reports.
+type countingObserver struct {
+	NopObserver
+	requests int64
+	weights  int64
+}
+
+func (o *countingObserver) RequestFinished(method, endpoint string, status int, duration time.Duration, err error) {
+	atomic.AddInt64(&o.requests, 1)
+}
+
+func (o *countingObserver) UsedWeight(interval string, weight int) {
+	atomic.AddInt64(&o.weights, 1)
+}
+
+// TestServiceConcurrentUse sends requests from many goroutines through one
+// service and its WithCredentials copy, so that -race reports unguarded
+// state updated by requests.
+func TestServiceConcurrentUse(t *testing.T) {
+	var clientOrderIDs sync.Map
+	var duplicates int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-MBX-USED-WEIGHT-1M", "10")
+		w.Header().Set("X-MBX-ORDER-COUNT-10S", "1")
+		switch r.URL.Path {
+		case "/api/v3/order":
+			id := r.URL.Query().Get("newClientOrderId")
+			if _, loaded := clientOrderIDs.LoadOrStore(id, true); loaded {
+				atomic.AddInt32(&duplicates, 1)
+			}
+			fmt.Fprintf(w, `{"symbol":"BNBBTC","orderId":1,"clientOrderId":"%s","transactTime":1499827319559}`, id)
+		case "/api/v3/account":
+			fmt.Fprint(w, `{"makerCommission":15,"canTrade":true,"balances":[{"asset":"BTC","free":"1.5","locked":"0"}]}`)
+		case "/api/v3/ticker/24hr":
+			fmt.Fprint(w, `{"symbol":"BNBBTC","priceChange":"-94.99","priceChangePercent":"-95.96",`+
+				`"weightedAvgPrice":"0.29","prevClosePrice":"0.10","lastPrice":"4.00","bidPrice":"4.00",`+
+				`"askPrice":"4.00","openPrice":"99.00","highPrice":"100.00","lowPrice":"0.10","volume":"8913.30",`+
+				`"openTime":1499783499040,"closeTime":1499869899040,"firstId":28385,"lastId":28460,"count":76}`)
+		case "/api/v1/exchangeInfo":
+			fmt.Fprint(w, `{"timezone":"UTC","serverTime":1565246363776,"rateLimits":[],"symbols":[]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"code":-1,"msg":"unknown endpoint"}`)
+		}
+	}
+	observer := &countingObserver{}
+	var hooked int64
+	as := newTestService(t, handler,
+		WithClientOrderIDs("test"),
+		WithCircuitBreaker(100, time.Second),
+		WithObserver(observer),
+		WithResponseHook(func(method, endpoint string, status int, body []byte) {
+			atomic.AddInt64(&hooked, 1)
+		}),
+		WithExchangeInfoTTL(time.Millisecond),
+	)
+	services := []Service{as, as.WithCredentials("other-key", &HmacSigner{Key: []byte("other")})}
+
+	const goroutines = 20
+	const rounds = 5
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines*rounds*4)
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(s Service) {
+			defer wg.Done()
+			for j := 0; j < rounds; j++ {
+				if _, err := s.NewOrder(NewOrderRequest{
+					Symbol:      "BNBBTC",
+					Side:        SideBuy,
+					Type:        TypeLimit,
+					TimeInForce: GTC,
+					Quantity:    1,
+					Price:       0.001,
+					Timestamp:   time.Now(),
+				}); err != nil {
+					errs <- err
+				}
+				if _, err := s.Account(AccountRequest{Timestamp: time.Now()}); err != nil {
+					errs <- err
+				}
+				if _, err := s.Ticker24(TickerRequest{Symbol: "BNBBTC"}); err != nil {
+					errs <- err
+				}
+				if _, err := s.CachedExchangeInfo(); err != nil {
+					errs <- err
+				}
+				s.OrderCount("10s")
+				s.CircuitState()
+			}
+		}(services[i%len(services)])
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	if n := atomic.LoadInt32(&duplicates); n != 0 {
+		t.Errorf("%d duplicate client order ids", n)
+	}
+	if got := as.OrderCount("10s"); got != 1 {
+		t.Errorf("OrderCount = %d, want 1", got)
+	}
+	if got, min := atomic.LoadInt64(&observer.requests), int64(goroutines*rounds*3); got < min {
+		t.Errorf("observed %d requests, want at least %d", got, min)
+	}
+	if got, want := atomic.LoadInt64(&hooked), atomic.LoadInt64(&observer.requests); got != want {
+		t.Errorf("response hook called %d times, want %d", got, want)
+	}
+}
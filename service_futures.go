@@ -0,0 +1,361 @@
+package binance
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+func (as *apiService) FuturesExchangeInfo() (*ExchangeInfo, error) {
+	params := make(map[string]string)
+
+	res, err := as.futuresRequest("GET", "fapi/v1/exchangeInfo", params, false, false)
+	if err != nil {
+		return nil, err
+	}
+	textRes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read response from fapi exchangeInfo")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return nil, as.handleError(textRes)
+	}
+
+	var exchangeInfo ExchangeInfo
+	if err := json.Unmarshal(textRes, &exchangeInfo); err != nil {
+		return nil, errors.Wrap(err, "futures exchangeInfo unmarshal failed")
+	}
+	return &exchangeInfo, nil
+}
+
+func (as *apiService) FuturesKlines(kr KlinesRequest) ([]*Kline, error) {
+	return as.klines(as.futuresRequest, "fapi/v1/klines", kr)
+}
+
+func (as *apiService) FuturesNewOrder(fnor FuturesNewOrderRequest) (*FuturesOrder, error) {
+	params := make(map[string]string)
+	params["symbol"] = NormalizeSymbol(fnor.Symbol)
+	params["side"] = string(fnor.Side)
+	params["type"] = string(fnor.Type)
+	params["quantity"] = strconv.FormatFloat(fnor.Quantity, 'f', -1, 64)
+	params["timestamp"] = strconv.FormatInt(unixMillis(fnor.Timestamp), 10)
+	if fnor.PositionSide != "" {
+		params["positionSide"] = string(fnor.PositionSide)
+	}
+	if fnor.TimeInForce != "" {
+		params["timeInForce"] = string(fnor.TimeInForce)
+	}
+	if fnor.Price != 0 {
+		params["price"] = strconv.FormatFloat(fnor.Price, 'f', -1, 64)
+	}
+	if fnor.StopPrice != 0 {
+		params["stopPrice"] = strconv.FormatFloat(fnor.StopPrice, 'f', -1, 64)
+	}
+	if fnor.ReduceOnly {
+		params["reduceOnly"] = "true"
+	}
+	if fnor.NewClientOrderID != "" {
+		params["newClientOrderId"] = fnor.NewClientOrderID
+	}
+	if fnor.RecvWindow != 0 {
+		params["recvWindow"] = strconv.FormatInt(recvWindow(fnor.RecvWindow), 10)
+	}
+
+	res, err := as.futuresRequest("POST", "fapi/v1/order", params, true, true)
+	if err != nil {
+		return nil, err
+	}
+	textRes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read response from fapi order.post")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return nil, as.handleError(textRes)
+	}
+
+	rawOrder := struct {
+		Symbol        string     `json:"symbol"`
+		OrderID       lenientInt `json:"orderId"`
+		ClientOrderID string     `json:"clientOrderId"`
+		Price         float64    `json:"price,string"`
+		AvgPrice      float64    `json:"avgPrice,string"`
+		OrigQty       float64    `json:"origQty,string"`
+		ExecutedQty   float64    `json:"executedQty,string"`
+		CumQuote      float64    `json:"cumQuote,string"`
+		Status        string     `json:"status"`
+		TimeInForce   string     `json:"timeInForce"`
+		Type          string     `json:"type"`
+		Side          string     `json:"side"`
+		PositionSide  string     `json:"positionSide"`
+		StopPrice     float64    `json:"stopPrice,string"`
+		ReduceOnly    bool       `json:"reduceOnly"`
+		UpdateTime    float64    `json:"updateTime"`
+	}{}
+	if err := json.Unmarshal(textRes, &rawOrder); err != nil {
+		return nil, errors.Wrap(err, "rawFuturesOrder unmarshal failed")
+	}
+
+	t, err := timeFromUnixTimestampFloat(rawOrder.UpdateTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FuturesOrder{
+		Symbol:        rawOrder.Symbol,
+		OrderID:       int64(rawOrder.OrderID),
+		ClientOrderID: rawOrder.ClientOrderID,
+		Price:         rawOrder.Price,
+		AvgPrice:      rawOrder.AvgPrice,
+		OrigQty:       rawOrder.OrigQty,
+		ExecutedQty:   rawOrder.ExecutedQty,
+		CumQuote:      rawOrder.CumQuote,
+		Status:        OrderStatus(rawOrder.Status),
+		TimeInForce:   TimeInForce(rawOrder.TimeInForce),
+		Type:          OrderType(rawOrder.Type),
+		Side:          OrderSide(rawOrder.Side),
+		PositionSide:  PositionSide(rawOrder.PositionSide),
+		StopPrice:     rawOrder.StopPrice,
+		ReduceOnly:    rawOrder.ReduceOnly,
+		UpdateTime:    t,
+	}, nil
+}
+
+func (as *apiService) FuturesAccount(ar AccountRequest) (*FuturesAccount, error) {
+	params := make(map[string]string)
+	params["timestamp"] = strconv.FormatInt(unixMillis(ar.Timestamp), 10)
+	if ar.RecvWindow != 0 {
+		params["recvWindow"] = strconv.FormatInt(recvWindow(ar.RecvWindow), 10)
+	}
+
+	res, err := as.futuresRequest("GET", "fapi/v2/account", params, true, true)
+	if err != nil {
+		return nil, err
+	}
+	textRes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read response from fapi account.get")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return nil, as.handleError(textRes)
+	}
+
+	rawAccount := struct {
+		TotalWalletBalance    float64         `json:"totalWalletBalance,string"`
+		TotalUnrealizedProfit float64         `json:"totalUnrealizedProfit,string"`
+		TotalMarginBalance    float64         `json:"totalMarginBalance,string"`
+		AvailableBalance      float64         `json:"availableBalance,string"`
+		MaxWithdrawAmount     float64         `json:"maxWithdrawAmount,string"`
+		CanTrade              bool            `json:"canTrade"`
+		CanDeposit            bool            `json:"canDeposit"`
+		CanWithdraw           bool            `json:"canWithdraw"`
+		UpdateTime            float64         `json:"updateTime"`
+		Assets                []*FuturesAsset `json:"assets"`
+	}{}
+	if err := json.Unmarshal(textRes, &rawAccount); err != nil {
+		return nil, errors.Wrap(err, "rawFuturesAccount unmarshal failed")
+	}
+
+	t, err := timeFromUnixTimestampFloat(rawAccount.UpdateTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FuturesAccount{
+		TotalWalletBalance:    rawAccount.TotalWalletBalance,
+		TotalUnrealizedProfit: rawAccount.TotalUnrealizedProfit,
+		TotalMarginBalance:    rawAccount.TotalMarginBalance,
+		AvailableBalance:      rawAccount.AvailableBalance,
+		MaxWithdrawAmount:     rawAccount.MaxWithdrawAmount,
+		CanTrade:              rawAccount.CanTrade,
+		CanDeposit:            rawAccount.CanDeposit,
+		CanWithdraw:           rawAccount.CanWithdraw,
+		UpdateTime:            t,
+		Assets:                rawAccount.Assets,
+	}, nil
+}
+
+func (as *apiService) FuturesPositionRisk(fprr FuturesPositionRiskRequest) ([]*FuturesPosition, error) {
+	params := make(map[string]string)
+	params["timestamp"] = strconv.FormatInt(unixMillis(fprr.Timestamp), 10)
+	if fprr.Symbol != "" {
+		params["symbol"] = NormalizeSymbol(fprr.Symbol)
+	}
+	if fprr.RecvWindow != 0 {
+		params["recvWindow"] = strconv.FormatInt(recvWindow(fprr.RecvWindow), 10)
+	}
+
+	res, err := as.futuresRequest("GET", "fapi/v2/positionRisk", params, true, true)
+	if err != nil {
+		return nil, err
+	}
+	textRes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read response from fapi positionRisk.get")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return nil, as.handleError(textRes)
+	}
+
+	rawPositions := []struct {
+		Symbol           string  `json:"symbol"`
+		PositionAmt      float64 `json:"positionAmt,string"`
+		EntryPrice       float64 `json:"entryPrice,string"`
+		MarkPrice        float64 `json:"markPrice,string"`
+		UnrealizedProfit float64 `json:"unRealizedProfit,string"`
+		LiquidationPrice float64 `json:"liquidationPrice,string"`
+		Leverage         string  `json:"leverage"`
+		MarginType       string  `json:"marginType"`
+		PositionSide     string  `json:"positionSide"`
+		UpdateTime       float64 `json:"updateTime"`
+	}{}
+	if err := json.Unmarshal(textRes, &rawPositions); err != nil {
+		return nil, errors.Wrap(err, "rawPositions unmarshal failed")
+	}
+
+	var pc []*FuturesPosition
+	for _, rp := range rawPositions {
+		leverage, err := intFromString(rp.Leverage)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot parse FuturesPosition.Leverage")
+		}
+		t, err := timeFromUnixTimestampFloat(rp.UpdateTime)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot parse FuturesPosition.UpdateTime")
+		}
+		pc = append(pc, &FuturesPosition{
+			Symbol:           rp.Symbol,
+			PositionAmt:      rp.PositionAmt,
+			EntryPrice:       rp.EntryPrice,
+			MarkPrice:        rp.MarkPrice,
+			UnrealizedProfit: rp.UnrealizedProfit,
+			LiquidationPrice: rp.LiquidationPrice,
+			Leverage:         leverage,
+			MarginType:       rp.MarginType,
+			PositionSide:     PositionSide(rp.PositionSide),
+			UpdateTime:       t,
+		})
+	}
+	return pc, nil
+}
+
+func (as *apiService) FundingRateHistory(frr FundingRateRequest) ([]*FundingRate, error) {
+	params := make(map[string]string)
+	if frr.Symbol != "" {
+		params["symbol"] = NormalizeSymbol(frr.Symbol)
+	}
+	if frr.StartTime != 0 {
+		params["startTime"] = strconv.FormatInt(frr.StartTime, 10)
+	}
+	if frr.EndTime != 0 {
+		params["endTime"] = strconv.FormatInt(frr.EndTime, 10)
+	}
+	if frr.Limit != 0 {
+		params["limit"] = strconv.Itoa(frr.Limit)
+	}
+
+	res, err := as.futuresRequest("GET", "fapi/v1/fundingRate", params, false, false)
+	if err != nil {
+		return nil, err
+	}
+	textRes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read response from fapi fundingRate")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return nil, as.handleError(textRes)
+	}
+
+	rawRates := []struct {
+		Symbol      string  `json:"symbol"`
+		FundingRate string  `json:"fundingRate"`
+		FundingTime float64 `json:"fundingTime"`
+		MarkPrice   string  `json:"markPrice"`
+	}{}
+	if err := json.Unmarshal(textRes, &rawRates); err != nil {
+		return nil, errors.Wrap(err, "rawFundingRates unmarshal failed")
+	}
+
+	var frc []*FundingRate
+	for _, rr := range rawRates {
+		rate, err := floatFromString(rr.FundingRate)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot parse FundingRate.FundingRate")
+		}
+		// markPrice is absent for rates settled before it was introduced
+		var markPrice float64
+		if rr.MarkPrice != "" {
+			markPrice, err = floatFromString(rr.MarkPrice)
+			if err != nil {
+				return nil, errors.Wrap(err, "cannot parse FundingRate.MarkPrice")
+			}
+		}
+		t, err := timeFromUnixTimestampFloat(rr.FundingTime)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot parse FundingRate.FundingTime")
+		}
+		frc = append(frc, &FundingRate{
+			Symbol:      rr.Symbol,
+			FundingRate: rate,
+			FundingTime: t,
+			MarkPrice:   markPrice,
+		})
+	}
+	return frc, nil
+}
+
+func (as *apiService) MarkPriceWebsocket(mpwr MarkPriceWebsocketRequest) (chan *MarkPriceEvent, chan struct{}, error) {
+	as = as.streamService()
+	url := fmt.Sprintf("wss://fstream.binance.com/ws/%s@markPrice", streamSymbol(mpwr.Symbol))
+
+	mpech := make(chan *MarkPriceEvent)
+	done, err := as.wsServe(url, func(message []byte) error {
+		rawMarkPrice := struct {
+			Type                 string  `json:"e"`
+			Time                 int64   `json:"E"`
+			Symbol               string  `json:"s"`
+			MarkPrice            float64 `json:"p,string"`
+			IndexPrice           float64 `json:"i,string"`
+			EstimatedSettlePrice float64 `json:"P,string"`
+			FundingRate          float64 `json:"r,string"`
+			NextFundingTime      int64   `json:"T"`
+		}{}
+		if err := json.Unmarshal(message, &rawMarkPrice); err != nil {
+			return errors.Wrap(err, "rawMarkPrice unmarshal failed")
+		}
+		mpe := &MarkPriceEvent{
+			WSEvent: WSEvent{
+				Type:   rawMarkPrice.Type,
+				Time:   timeFromUnixMillis(rawMarkPrice.Time),
+				Symbol: rawMarkPrice.Symbol,
+				Raw:    as.rawEvent(message),
+			},
+			MarkPrice:            rawMarkPrice.MarkPrice,
+			IndexPrice:           rawMarkPrice.IndexPrice,
+			EstimatedSettlePrice: rawMarkPrice.EstimatedSettlePrice,
+			FundingRate:          rawMarkPrice.FundingRate,
+			NextFundingTime:      timeFromUnixMillis(rawMarkPrice.NextFundingTime),
+		}
+		select {
+		case mpech <- mpe:
+		case <-as.Ctx.Done():
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return mpech, done, nil
+}
@@ -0,0 +1,197 @@
+package binance
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFuturesFixtures(t *testing.T) {
+	now := time.Now()
+	testRESTFixtures(t, []restFixture{
+		{
+			name:    "FuturesExchangeInfo",
+			method:  "GET",
+			path:    "/fapi/v1/exchangeInfo",
+			fixture: "futures_exchange_info.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.FuturesExchangeInfo()
+			},
+			check: func(t *testing.T, got interface{}) {
+				info := got.(*ExchangeInfo)
+				if len(info.Symbols) != 1 {
+					t.Fatalf("got %d symbols, want 1", len(info.Symbols))
+				}
+				filters := info.Symbols[0].Filters
+				info.Symbols[0].Filters = nil
+				checkFields(t, info, &ExchangeInfo{
+					TimeZone:   "UTC",
+					ServerTime: 1565613908500,
+					RateLimits: []RateLimit{
+						{RateLimitType: RateLimitRequestWeight, Interval: RateLimitMinute, IntervalNum: 1, Limit: 2400},
+						{RateLimitType: RateLimitOrders, Interval: RateLimitMinute, IntervalNum: 1, Limit: 1200},
+					},
+					ExchangeFilters: []interface{}{},
+					Symbols: []Symbol{
+						{
+							Asset:              "BTCUSDT",
+							Status:             "TRADING",
+							BaseAsset:          "BTC",
+							BaseAssetPrecision: 8,
+							QuoteAsset:         "USDT",
+							QuotePrecision:     8,
+							OrderTypes:         []string{"LIMIT", "MARKET", "STOP"},
+						},
+					},
+				})
+				if len(filters) != 2 || filters[0].FilterType != "PRICE_FILTER" || filters[0].MinPrice != 556.8 ||
+					filters[0].MaxPrice != 4529764 || filters[1].FilterType != "LOT_SIZE" || filters[1].StepSize != 0.001 {
+					t.Errorf("unexpected filters %+v", filters)
+				}
+			},
+		},
+		{
+			name:    "FuturesKlines",
+			method:  "GET",
+			path:    "/fapi/v1/klines",
+			fixture: "futures_klines.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.FuturesKlines(KlinesRequest{Symbol: "BTCUSDT", Interval: Week})
+			},
+			want: []*Kline{fixtureKline},
+		},
+		{
+			name:    "FuturesNewOrder",
+			method:  "POST",
+			path:    "/fapi/v1/order",
+			fixture: "futures_order_new.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.FuturesNewOrder(FuturesNewOrderRequest{
+					Symbol:       "BTCUSDT",
+					Side:         SideBuy,
+					PositionSide: PositionSideShort,
+					Type:         OrderType("TRAILING_STOP_MARKET"),
+					Quantity:     10,
+					Timestamp:    now,
+				})
+			},
+			want: &FuturesOrder{
+				Symbol:        "BTCUSDT",
+				OrderID:       22542179,
+				ClientOrderID: "testOrder",
+				OrigQty:       10,
+				Status:        StatusNew,
+				TimeInForce:   TimeInForce("GTD"),
+				Type:          OrderType("TRAILING_STOP_MARKET"),
+				Side:          SideBuy,
+				PositionSide:  PositionSideShort,
+				StopPrice:     9300,
+				UpdateTime:    timeFromUnixMillis(1566818724722),
+			},
+		},
+		{
+			name:    "FuturesAccount",
+			method:  "GET",
+			path:    "/fapi/v2/account",
+			fixture: "futures_account.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.FuturesAccount(AccountRequest{Timestamp: now})
+			},
+			want: &FuturesAccount{
+				TotalWalletBalance: 23.72469206,
+				TotalMarginBalance: 23.72469206,
+				AvailableBalance:   23.72469206,
+				MaxWithdrawAmount:  23.72469206,
+				CanTrade:           true,
+				CanDeposit:         true,
+				CanWithdraw:        true,
+				UpdateTime:         timeFromUnixMillis(1618909432000),
+				Assets: []*FuturesAsset{
+					{
+						Asset:            "USDT",
+						WalletBalance:    23.72469206,
+						MarginBalance:    23.72469206,
+						AvailableBalance: 23.72469206,
+					},
+					{
+						Asset:            "BUSD",
+						WalletBalance:    103.12345678,
+						UnrealizedProfit: 1.5,
+						MarginBalance:    104.62345678,
+						AvailableBalance: 100.12345678,
+					},
+				},
+			},
+		},
+		{
+			name:    "FuturesPositionRisk",
+			method:  "GET",
+			path:    "/fapi/v2/positionRisk",
+			fixture: "futures_position_risk.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.FuturesPositionRisk(FuturesPositionRiskRequest{Symbol: "BTCUSDT", Timestamp: now})
+			},
+			want: []*FuturesPosition{
+				{
+					Symbol:           "BTCUSDT",
+					PositionAmt:      20,
+					EntryPrice:       6563.665,
+					MarkPrice:        6679.50671178,
+					UnrealizedProfit: 2316.8342356,
+					LiquidationPrice: 5930.78,
+					Leverage:         10,
+					MarginType:       "isolated",
+					PositionSide:     PositionSideLong,
+					UpdateTime:       timeFromUnixMillis(1625474304765),
+				},
+			},
+		},
+		{
+			name:    "FundingRateHistory",
+			method:  "GET",
+			path:    "/fapi/v1/fundingRate",
+			fixture: "funding_rate.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.FundingRateHistory(FundingRateRequest{Symbol: "BTCUSDT"})
+			},
+			want: []*FundingRate{
+				{
+					Symbol:      "BTCUSDT",
+					FundingRate: -0.0375,
+					FundingTime: timeFromUnixMillis(1570608000000),
+					MarkPrice:   34287.54619963,
+				},
+				{
+					Symbol:      "BTCUSDT",
+					FundingRate: 0.0001,
+					FundingTime: timeFromUnixMillis(1570636800000),
+				},
+			},
+		},
+	})
+}
+
+func TestMarkPriceStreamFixture(t *testing.T) {
+	testStreamFixtures(t, []streamFixture{
+		{
+			name:    "MarkPriceWebsocket",
+			path:    "/ws/btcusdt@markPrice",
+			fixture: "ws_mark_price.json",
+			start: func(as *apiService) (interface{}, error) {
+				mpech, _, err := as.MarkPriceWebsocket(MarkPriceWebsocketRequest{Symbol: "BTCUSDT"})
+				return mpech, err
+			},
+			want: &MarkPriceEvent{
+				WSEvent: WSEvent{
+					Type:   "markPriceUpdate",
+					Time:   timeFromUnixMillis(1562305380000),
+					Symbol: "BTCUSDT",
+				},
+				MarkPrice:            11794.15,
+				IndexPrice:           11784.62659091,
+				EstimatedSettlePrice: 11784.25641265,
+				FundingRate:          0.00038167,
+				NextFundingTime:      timeFromUnixMillis(1562306400000),
+			},
+		},
+	})
+}
@@ -0,0 +1,134 @@
+package binance
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+func (as *apiService) LendingProductList(lplr LendingProductListRequest) ([]*LendingProduct, error) {
+	params := make(map[string]string)
+	params["timestamp"] = strconv.FormatInt(unixMillis(lplr.Timestamp), 10)
+	if lplr.Asset != "" {
+		params["asset"] = lplr.Asset
+	}
+	if lplr.Current != 0 {
+		params["current"] = strconv.Itoa(lplr.Current)
+	}
+	if lplr.Size != 0 {
+		params["size"] = strconv.Itoa(lplr.Size)
+	}
+	if lplr.RecvWindow != 0 {
+		params["recvWindow"] = strconv.FormatInt(recvWindow(lplr.RecvWindow), 10)
+	}
+
+	res, err := as.request("GET", "sapi/v1/simple-earn/flexible/list", params, true, true)
+	if err != nil {
+		return nil, err
+	}
+	textRes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read response from simple-earn/flexible/list.get")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return nil, as.handleError(textRes)
+	}
+
+	rawList := struct {
+		Rows  []*LendingProduct `json:"rows"`
+		Total int               `json:"total"`
+	}{}
+	if err := json.Unmarshal(textRes, &rawList); err != nil {
+		return nil, errors.Wrap(err, "rawLendingProducts unmarshal failed")
+	}
+	return rawList.Rows, nil
+}
+
+func (as *apiService) LendingPurchase(lpr LendingPurchaseRequest) (*LendingPurchaseResult, error) {
+	params := make(map[string]string)
+	params["productId"] = lpr.ProductID
+	params["amount"] = strconv.FormatFloat(lpr.Amount, 'f', -1, 64)
+	params["timestamp"] = strconv.FormatInt(unixMillis(lpr.Timestamp), 10)
+	if lpr.AutoSubscribe != nil {
+		params["autoSubscribe"] = strconv.FormatBool(*lpr.AutoSubscribe)
+	}
+	if lpr.RecvWindow != 0 {
+		params["recvWindow"] = strconv.FormatInt(recvWindow(lpr.RecvWindow), 10)
+	}
+
+	res, err := as.request("POST", "sapi/v1/simple-earn/flexible/subscribe", params, true, true)
+	if err != nil {
+		return nil, err
+	}
+	textRes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read response from simple-earn/flexible/subscribe.post")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return nil, as.handleError(textRes)
+	}
+
+	rawResult := struct {
+		PurchaseID int64 `json:"purchaseId"`
+		Success    bool  `json:"success"`
+	}{}
+	if err := json.Unmarshal(textRes, &rawResult); err != nil {
+		return nil, errors.Wrap(err, "rawLendingPurchase unmarshal failed")
+	}
+
+	return &LendingPurchaseResult{
+		PurchaseID: rawResult.PurchaseID,
+		Success:    rawResult.Success,
+	}, nil
+}
+
+func (as *apiService) LendingRedeem(lrr LendingRedeemRequest) (*LendingRedeemResult, error) {
+	params := make(map[string]string)
+	params["productId"] = lrr.ProductID
+	params["timestamp"] = strconv.FormatInt(unixMillis(lrr.Timestamp), 10)
+	if lrr.RedeemAll {
+		params["redeemAll"] = "true"
+	}
+	if lrr.Amount != 0 {
+		params["amount"] = strconv.FormatFloat(lrr.Amount, 'f', -1, 64)
+	}
+	if lrr.DestAccount != "" {
+		params["destAccount"] = lrr.DestAccount
+	}
+	if lrr.RecvWindow != 0 {
+		params["recvWindow"] = strconv.FormatInt(recvWindow(lrr.RecvWindow), 10)
+	}
+
+	res, err := as.request("POST", "sapi/v1/simple-earn/flexible/redeem", params, true, true)
+	if err != nil {
+		return nil, err
+	}
+	textRes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read response from simple-earn/flexible/redeem.post")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return nil, as.handleError(textRes)
+	}
+
+	rawResult := struct {
+		RedeemID int64 `json:"redeemId"`
+		Success  bool  `json:"success"`
+	}{}
+	if err := json.Unmarshal(textRes, &rawResult); err != nil {
+		return nil, errors.Wrap(err, "rawLendingRedeem unmarshal failed")
+	}
+
+	return &LendingRedeemResult{
+		RedeemID: rawResult.RedeemID,
+		Success:  rawResult.Success,
+	}, nil
+}
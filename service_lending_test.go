@@ -0,0 +1,53 @@
+package binance
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLendingFixtures(t *testing.T) {
+	now := time.Now()
+	testRESTFixtures(t, []restFixture{
+		{
+			name:    "LendingProductList",
+			method:  "GET",
+			path:    "/sapi/v1/simple-earn/flexible/list",
+			fixture: "lending_product_list.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.LendingProductList(LendingProductListRequest{Asset: "BTC", Timestamp: now})
+			},
+			want: []*LendingProduct{
+				{
+					ProductID:                  "BTC001",
+					Asset:                      "BTC",
+					LatestAnnualPercentageRate: 0.05,
+					MinPurchaseAmount:          0.01,
+					CanPurchase:                true,
+					CanRedeem:                  true,
+					IsSoldOut:                  true,
+					Status:                     "PURCHASING",
+				},
+			},
+		},
+		{
+			name:    "LendingPurchase",
+			method:  "POST",
+			path:    "/sapi/v1/simple-earn/flexible/subscribe",
+			fixture: "lending_purchase.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.LendingPurchase(LendingPurchaseRequest{ProductID: "BTC001", Amount: 0.1, Timestamp: now})
+			},
+			want: &LendingPurchaseResult{PurchaseID: 40607, Success: true},
+		},
+		{
+			name:    "LendingRedeem",
+			method:  "POST",
+			path:    "/sapi/v1/simple-earn/flexible/redeem",
+			fixture: "lending_redeem.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.LendingRedeem(LendingRedeemRequest{ProductID: "BTC001", RedeemAll: true, Timestamp: now})
+			},
+			want: &LendingRedeemResult{RedeemID: 40607, Success: true},
+		},
+	})
+}
@@ -8,7 +8,6 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
-	"strings"
 )
 
 func (as *apiService) Ping() error {
@@ -32,23 +31,26 @@ func (as *apiService) Time() (time.Time, error) {
 		return time.Time{}, errors.Wrap(err, "unable to read response from Time")
 	}
 	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return time.Time{}, as.handleError(textRes)
+	}
 	var rawTime struct {
-		ServerTime string `json:"serverTime"`
+		ServerTime int64 `json:"serverTime"`
 	}
 	if err := json.Unmarshal(textRes, &rawTime); err != nil {
 		return time.Time{}, errors.Wrap(err, "timeResponse unmarshal failed")
 	}
-	t, err := timeFromUnixTimestampFloat(rawTime)
-	if err != nil {
-		return time.Time{}, err
-	}
-	return t, nil
+	return timeFromUnixMillis(rawTime.ServerTime), nil
 }
 
 func (as *apiService) OrderBook(obr OrderBookRequest) (*OrderBook, error) {
 	params := make(map[string]string)
-	params["symbol"] = obr.Symbol
+	params["symbol"] = NormalizeSymbol(obr.Symbol)
 	if obr.Limit != 0 {
+		if _, ok := orderBookWeights[obr.Limit]; !ok {
+			return nil, errors.Errorf("invalid order book limit %d, allowed: 5, 10, 20, 50, 100, 500, 1000, 5000", obr.Limit)
+		}
 		params["limit"] = strconv.Itoa(obr.Limit)
 	}
 	res, err := as.request("GET", "api/v1/depth", params, false, false)
@@ -62,11 +64,11 @@ func (as *apiService) OrderBook(obr OrderBookRequest) (*OrderBook, error) {
 	defer res.Body.Close()
 
 	if res.StatusCode != 200 {
-		as.handleError(textRes)
+		return nil, as.handleError(textRes)
 	}
 
 	rawBook := &struct {
-		LastUpdateID int             `json:"lastUpdateId"`
+		LastUpdateID lenientInt      `json:"lastUpdateId"`
 		Bids         [][]interface{} `json:"bids"`
 		Asks         [][]interface{} `json:"asks"`
 	}{}
@@ -75,7 +77,7 @@ func (as *apiService) OrderBook(obr OrderBookRequest) (*OrderBook, error) {
 	}
 
 	ob := &OrderBook{
-		LastUpdateID: rawBook.LastUpdateID,
+		LastUpdateID: int(rawBook.LastUpdateID),
 	}
 	extractOrder := func(rawPrice, rawQuantity interface{}) (*Order, error) {
 		price, err := floatFromString(rawPrice)
@@ -87,8 +89,10 @@ func (as *apiService) OrderBook(obr OrderBookRequest) (*OrderBook, error) {
 			return nil, err
 		}
 		return &Order{
-			Price:    price,
-			Quantity: quantity,
+			Price:       price,
+			Quantity:    quantity,
+			RawPrice:    rawPrice.(string),
+			RawQuantity: rawQuantity.(string),
 		}, nil
 	}
 	for _, bid := range rawBook.Bids {
@@ -111,7 +115,7 @@ func (as *apiService) OrderBook(obr OrderBookRequest) (*OrderBook, error) {
 
 func (as *apiService) AggTrades(atr AggTradesRequest) ([]*AggTrade, error) {
 	params := make(map[string]string)
-	params["symbol"] = atr.Symbol
+	params["symbol"] = NormalizeSymbol(atr.Symbol)
 	if atr.FromID != 0 {
 		params["fromId"] = strconv.FormatInt(atr.FromID, 10)
 	}
@@ -136,7 +140,7 @@ func (as *apiService) AggTrades(atr AggTradesRequest) ([]*AggTrade, error) {
 	defer res.Body.Close()
 
 	if res.StatusCode != 200 {
-		as.handleError(textRes)
+		return nil, as.handleError(textRes)
 	}
 
 	rawAggTrades := []struct {
@@ -162,7 +166,7 @@ func (as *apiService) AggTrades(atr AggTradesRequest) ([]*AggTrade, error) {
 		if err != nil {
 			return nil, err
 		}
-		t := time.Unix(0, rawTrade.Timestamp*int64(time.Millisecond))
+		t := timeFromUnixMillis(rawTrade.Timestamp)
 
 		aggTrades = append(aggTrades, &AggTrade{
 			ID:             rawTrade.ID,
@@ -173,6 +177,8 @@ func (as *apiService) AggTrades(atr AggTradesRequest) ([]*AggTrade, error) {
 			Timestamp:      t,
 			BuyerMaker:     rawTrade.BuyerMaker,
 			BestPriceMatch: rawTrade.BestPriceMatch,
+			RawPrice:       rawTrade.Price,
+			RawQuantity:    rawTrade.Quantity,
 		})
 	}
 	return aggTrades, nil
@@ -192,7 +198,7 @@ func (as *apiService) ExchangeInfo() (*ExchangeInfo, error) {
 	defer res.Body.Close()
 
 	if res.StatusCode != 200 {
-		as.handleError(textRes)
+		return nil, as.handleError(textRes)
 	}
 
 	var exchangeInfo ExchangeInfo
@@ -205,26 +211,27 @@ func (as *apiService) ExchangeInfo() (*ExchangeInfo, error) {
 
 func (as *apiService) HistoricalTrades(htr HistoricalTradesRequest) (ht []*HistoricalTrades, err error) {
 	params := make(map[string]string)
-	params["symbol"] = strings.ToUpper(htr.Symbol)
-	if htr.FromId >= 0 {
+	params["symbol"] = NormalizeSymbol(htr.Symbol)
+	if htr.FromId != 0 {
 		params["fromId"] = strconv.FormatInt(htr.FromId, 10)
 	}
-	if htr.Limit >= 0 && htr.Limit <= 500 {
+	if htr.Limit != 0 {
 		params["limit"] = strconv.Itoa(htr.Limit)
 	}
 
+	// MARKET_DATA endpoint, API key is required while signature isn't
 	res, err := as.request("GET", "api/v1/historicalTrades", params, true, false)
 	if err != nil {
 		return ht, err
 	}
 	textRes, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return ht, errors.Wrap(err, "unable to read response from AggTrades")
+		return ht, errors.Wrap(err, "unable to read response from historicalTrades")
 	}
 	defer res.Body.Close()
 
 	if res.StatusCode != 200 {
-		as.handleError(textRes)
+		return nil, as.handleError(textRes)
 	}
 
 	//	historyTrades := new([]*HistoricalTrades)
@@ -237,8 +244,16 @@ func (as *apiService) HistoricalTrades(htr HistoricalTradesRequest) (ht []*Histo
 }
 
 func (as *apiService) Klines(kr KlinesRequest) ([]*Kline, error) {
+	return as.klines(as.request, "api/v1/klines", kr)
+}
+
+func (as *apiService) UIKlines(kr KlinesRequest) ([]*Kline, error) {
+	return as.klines(as.request, "api/v3/uiKlines", kr)
+}
+
+func (as *apiService) klines(request requestFunc, endpoint string, kr KlinesRequest) ([]*Kline, error) {
 	params := make(map[string]string)
-	params["symbol"] = kr.Symbol
+	params["symbol"] = NormalizeSymbol(kr.Symbol)
 	params["interval"] = string(kr.Interval)
 	if kr.Limit != 0 {
 		params["limit"] = strconv.Itoa(kr.Limit)
@@ -250,7 +265,7 @@ func (as *apiService) Klines(kr KlinesRequest) ([]*Kline, error) {
 		params["endTime"] = strconv.FormatInt(kr.EndTime, 10)
 	}
 
-	res, err := as.request("GET", "api/v1/klines", params, false, false)
+	res, err := request("GET", endpoint, params, false, false)
 	if err != nil {
 		return nil, err
 	}
@@ -261,7 +276,7 @@ func (as *apiService) Klines(kr KlinesRequest) ([]*Kline, error) {
 	defer res.Body.Close()
 
 	if res.StatusCode != 200 {
-		as.handleError(textRes)
+		return nil, as.handleError(textRes)
 	}
 
 	rawKlines := [][]interface{}{}
@@ -326,16 +341,31 @@ func (as *apiService) Klines(kr KlinesRequest) ([]*Kline, error) {
 			NumberOfTrades:           int(not),
 			TakerBuyBaseAssetVolume:  tbbav,
 			TakerBuyQuoteAssetVolume: tbqav,
+
+			RawOpen:                     k[1].(string),
+			RawHigh:                     k[2].(string),
+			RawLow:                      k[3].(string),
+			RawClose:                    k[4].(string),
+			RawVolume:                   k[5].(string),
+			RawQuoteAssetVolume:         k[7].(string),
+			RawTakerBuyBaseAssetVolume:  k[9].(string),
+			RawTakerBuyQuoteAssetVolume: k[10].(string),
 		})
 	}
+	if n := len(klines); kr.ClosedOnly && n > 0 && klines[n-1].CloseTime.After(time.Now()) {
+		klines = klines[:n-1]
+	}
 	return klines, nil
 }
 
 func (as *apiService) Ticker24(tr TickerRequest) (*Ticker24, error) {
 	params := make(map[string]string)
-	params["symbol"] = tr.Symbol
+	params["symbol"] = NormalizeSymbol(tr.Symbol)
+	if tr.Type != "" {
+		params["type"] = string(tr.Type)
+	}
 
-	res, err := as.request("GET", "api/v1/ticker/24hr", params, false, false)
+	res, err := as.request("GET", "api/v3/ticker/24hr", params, false, false)
 	if err != nil {
 		return nil, err
 	}
@@ -346,44 +376,94 @@ func (as *apiService) Ticker24(tr TickerRequest) (*Ticker24, error) {
 	defer res.Body.Close()
 
 	if res.StatusCode != 200 {
-		as.handleError(textRes)
-	}
-
-	rawTicker24 := struct {
-		PriceChange        string  `json:"priceChange"`
-		PriceChangePercent string  `json:"priceChangePercent"`
-		WeightedAvgPrice   string  `json:"weightedAvgPrice"`
-		PrevClosePrice     string  `json:"prevClosePrice"`
-		LastPrice          string  `json:"lastPrice"`
-		BidPrice           string  `json:"bidPrice"`
-		AskPrice           string  `json:"askPrice"`
-		OpenPrice          string  `json:"openPrice"`
-		HighPrice          string  `json:"highPrice"`
-		LowPrice           string  `json:"lowPrice"`
-		Volume             string  `json:"volume"`
-		OpenTime           float64 `json:"openTime"`
-		CloseTime          float64 `json:"closeTime"`
-		FirstID            int
-		LastID             int
-		Count              int
-	}{}
-	if err := json.Unmarshal(textRes, &rawTicker24); err != nil {
+		return nil, as.handleError(textRes)
+	}
+
+	rawTicker := &rawTicker24{}
+	if err := json.Unmarshal(textRes, rawTicker); err != nil {
 		return nil, errors.Wrap(err, "rawTicker24 unmarshal failed")
 	}
+	return ticker24FromRaw(rawTicker)
+}
 
-	pc, err := strconv.ParseFloat(rawTicker24.PriceChange, 64)
+func (as *apiService) Tickers24(tr TickerRequest) ([]*Ticker24, error) {
+	symbols := make([]string, 0, len(tr.Symbols))
+	for _, s := range tr.Symbols {
+		symbols = append(symbols, NormalizeSymbol(s))
+	}
+	rawSymbols, err := json.Marshal(symbols)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to encode symbols")
+	}
+	params := make(map[string]string)
+	params["symbols"] = string(rawSymbols)
+	if tr.Type != "" {
+		params["type"] = string(tr.Type)
+	}
+
+	res, err := as.request("GET", "api/v3/ticker/24hr", params, false, false)
+	if err != nil {
+		return nil, err
+	}
+	textRes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read response from Ticker/24hr")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return nil, as.handleError(textRes)
+	}
+
+	rawTickers := []*rawTicker24{}
+	if err := json.Unmarshal(textRes, &rawTickers); err != nil {
+		return nil, errors.Wrap(err, "rawTickers24 unmarshal failed")
+	}
+	var tickers []*Ticker24
+	for _, rawTicker := range rawTickers {
+		t24, err := ticker24FromRaw(rawTicker)
+		if err != nil {
+			return nil, err
+		}
+		tickers = append(tickers, t24)
+	}
+	return tickers, nil
+}
+
+type rawTicker24 struct {
+	Symbol             string  `json:"symbol"`
+	PriceChange        string  `json:"priceChange"`
+	PriceChangePercent string  `json:"priceChangePercent"`
+	WeightedAvgPrice   string  `json:"weightedAvgPrice"`
+	PrevClosePrice     string  `json:"prevClosePrice"`
+	LastPrice          string  `json:"lastPrice"`
+	BidPrice           string  `json:"bidPrice"`
+	AskPrice           string  `json:"askPrice"`
+	OpenPrice          string  `json:"openPrice"`
+	HighPrice          string  `json:"highPrice"`
+	LowPrice           string  `json:"lowPrice"`
+	Volume             string  `json:"volume"`
+	OpenTime           float64 `json:"openTime"`
+	CloseTime          float64 `json:"closeTime"`
+	FirstID            int
+	LastID             int
+	Count              int
+}
+
+func ticker24FromRaw(rawTicker24 *rawTicker24) (*Ticker24, error) {
+	pc, err := optionalFloat(rawTicker24.PriceChange)
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot parse Ticker24.PriceChange")
 	}
-	pcPercent, err := strconv.ParseFloat(rawTicker24.PriceChangePercent, 64)
+	pcPercent, err := optionalFloat(rawTicker24.PriceChangePercent)
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot parse Ticker24.PriceChangePercent")
 	}
-	wap, err := strconv.ParseFloat(rawTicker24.WeightedAvgPrice, 64)
+	wap, err := optionalFloat(rawTicker24.WeightedAvgPrice)
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot parse Ticker24.WeightedAvgPrice")
 	}
-	pcp, err := strconv.ParseFloat(rawTicker24.PrevClosePrice, 64)
+	pcp, err := optionalFloat(rawTicker24.PrevClosePrice)
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot parse Ticker24.PrevClosePrice")
 	}
@@ -391,11 +471,11 @@ func (as *apiService) Ticker24(tr TickerRequest) (*Ticker24, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot parse Ticker24.LastPrice")
 	}
-	bp, err := strconv.ParseFloat(rawTicker24.BidPrice, 64)
+	bp, err := optionalFloat(rawTicker24.BidPrice)
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot parse Ticker24.BidPrice")
 	}
-	ap, err := strconv.ParseFloat(rawTicker24.AskPrice, 64)
+	ap, err := optionalFloat(rawTicker24.AskPrice)
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot parse Ticker24.AskPrice")
 	}
@@ -424,6 +504,7 @@ func (as *apiService) Ticker24(tr TickerRequest) (*Ticker24, error) {
 		return nil, errors.Wrap(err, "cannot parse Ticker24.CloseTime")
 	}
 	t24 := &Ticker24{
+		Symbol:             rawTicker24.Symbol,
 		PriceChange:        pc,
 		PriceChangePercent: pcPercent,
 		WeightedAvgPrice:   wap,
@@ -458,7 +539,7 @@ func (as *apiService) TickerAllPrices() ([]*PriceTicker, error) {
 	defer res.Body.Close()
 
 	if res.StatusCode != 200 {
-		as.handleError(textRes)
+		return nil, as.handleError(textRes)
 	}
 
 	rawTickerAllPrices := []struct {
@@ -0,0 +1,362 @@
+package binance
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestHistoricalTradesAPIKeyHeader(t *testing.T) {
+	as := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/historicalTrades" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if key := r.Header.Get("X-MBX-APIKEY"); key != testAPIKey {
+			t.Errorf("X-MBX-APIKEY = %q, want %q", key, testAPIKey)
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"code":-2015,"msg":"Invalid API-key, IP, or permissions for action."}`)
+			return
+		}
+		if sig := r.URL.Query().Get("signature"); sig != "" {
+			t.Errorf("unexpected signature %q", sig)
+		}
+		fmt.Fprint(w, `[{"id":28457,"price":"4.00000100","qty":"12.00000000","time":1499865549590,`+
+			`"isBuyerMaker":true,"isBestMatch":true}]`)
+	})
+
+	trades, err := as.HistoricalTrades(HistoricalTradesRequest{Symbol: "bnbbtc", Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trades) != 1 || trades[0].TradeId != 28457 || trades[0].Price != 4.000001 {
+		t.Errorf("unexpected trades %+v", trades)
+	}
+}
+
+func TestMarketFixtures(t *testing.T) {
+	klineOpen := timeFromUnixMillis(1499783499040)
+	klineClose := timeFromUnixMillis(1499869899040)
+	testRESTFixtures(t, []restFixture{
+		{
+			name:    "Ping",
+			method:  "GET",
+			path:    "/api/v1/ping",
+			fixture: "empty.json",
+			call: func(as *apiService) (interface{}, error) {
+				return nil, as.Ping()
+			},
+		},
+		{
+			name:    "Time",
+			method:  "GET",
+			path:    "/api/v1/time",
+			fixture: "time.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.Time()
+			},
+			want: timeFromUnixMillis(1499827319559),
+		},
+		{
+			name:    "OrderBook",
+			method:  "GET",
+			path:    "/api/v1/depth",
+			fixture: "depth.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.OrderBook(OrderBookRequest{Symbol: "BNBBTC", Limit: 5})
+			},
+			want: &OrderBook{
+				LastUpdateID: 1027024,
+				Bids: []*Order{
+					{Price: 4, Quantity: 431, RawPrice: "4.00000000", RawQuantity: "431.00000000"},
+					{Price: 3.99, Quantity: 12.5, RawPrice: "3.99000000", RawQuantity: "12.50000000"},
+				},
+				Asks: []*Order{
+					{Price: 4.000002, Quantity: 12, RawPrice: "4.00000200", RawQuantity: "12.00000000"},
+				},
+			},
+		},
+		{
+			name:    "AggTrades",
+			method:  "GET",
+			path:    "/api/v1/aggTrades",
+			fixture: "agg_trades.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.AggTrades(AggTradesRequest{Symbol: "ETHBTC"})
+			},
+			want: []*AggTrade{
+				{
+					ID:             26129,
+					Price:          0.01633102,
+					Quantity:       4.70443515,
+					FirstTradeID:   27781,
+					LastTradeID:    27781,
+					Timestamp:      timeFromUnixMillis(1498793709153),
+					BuyerMaker:     true,
+					BestPriceMatch: true,
+					RawPrice:       "0.01633102",
+					RawQuantity:    "4.70443515",
+				},
+				{
+					ID:             26130,
+					Price:          0.016332,
+					Quantity:       0.1,
+					FirstTradeID:   27782,
+					LastTradeID:    27784,
+					Timestamp:      timeFromUnixMillis(1498793709260),
+					BuyerMaker:     false,
+					BestPriceMatch: true,
+					RawPrice:       "0.01633200",
+					RawQuantity:    "0.10000000",
+				},
+			},
+		},
+		{
+			name:    "AggTrades error",
+			method:  "GET",
+			path:    "/api/v1/aggTrades",
+			fixture: "error_invalid_symbol.json",
+			status:  http.StatusBadRequest,
+			call: func(as *apiService) (interface{}, error) {
+				return as.AggTrades(AggTradesRequest{Symbol: "XXX"})
+			},
+			wantErr: &Error{Code: -1121, Message: "Invalid symbol."},
+		},
+		{
+			name:    "ExchangeInfo",
+			method:  "GET",
+			path:    "/api/v1/exchangeInfo",
+			fixture: "exchange_info.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.ExchangeInfo()
+			},
+			check: checkExchangeInfoFixture,
+		},
+		{
+			name:    "ExchangeInfo error",
+			method:  "GET",
+			path:    "/api/v1/exchangeInfo",
+			fixture: "error_invalid_symbol.json",
+			status:  http.StatusBadRequest,
+			call: func(as *apiService) (interface{}, error) {
+				return as.ExchangeInfo()
+			},
+			wantErr: &Error{Code: -1121, Message: "Invalid symbol."},
+		},
+		{
+			name:    "HistoricalTrades",
+			method:  "GET",
+			path:    "/api/v1/historicalTrades",
+			fixture: "historical_trades.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.HistoricalTrades(HistoricalTradesRequest{Symbol: "BNBBTC"})
+			},
+			want: []*HistoricalTrades{
+				{
+					TradeId:    28457,
+					Price:      4.000001,
+					Quantity:   12,
+					TradeTime:  1499865549590,
+					BuyerMaker: true,
+					BestMatch:  true,
+				},
+			},
+		},
+		{
+			name:    "Klines",
+			method:  "GET",
+			path:    "/api/v1/klines",
+			fixture: "klines.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.Klines(KlinesRequest{Symbol: "BNBBTC", Interval: Week})
+			},
+			want: []*Kline{fixtureKline},
+		},
+		{
+			name:    "UIKlines",
+			method:  "GET",
+			path:    "/api/v3/uiKlines",
+			fixture: "klines.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.UIKlines(KlinesRequest{Symbol: "BNBBTC", Interval: Week})
+			},
+			want: []*Kline{fixtureKline},
+		},
+		{
+			name:    "Ticker24",
+			method:  "GET",
+			path:    "/api/v3/ticker/24hr",
+			fixture: "ticker_24hr.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.Ticker24(TickerRequest{Symbol: "BNBBTC"})
+			},
+			want: &Ticker24{
+				Symbol:             "BNBBTC",
+				PriceChange:        -94.999998,
+				PriceChangePercent: -95.96,
+				WeightedAvgPrice:   0.29628482,
+				PrevClosePrice:     0.10002,
+				LastPrice:          4.000002,
+				BidPrice:           4,
+				AskPrice:           4.000002,
+				OpenPrice:          99,
+				HighPrice:          100,
+				LowPrice:           0.1,
+				Volume:             8913.3,
+				OpenTime:           klineOpen,
+				CloseTime:          klineClose,
+				FirstID:            28385,
+				LastID:             28460,
+				Count:              76,
+			},
+		},
+		{
+			name:    "Tickers24 mini",
+			method:  "GET",
+			path:    "/api/v3/ticker/24hr",
+			fixture: "tickers_24hr_mini.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.Tickers24(TickerRequest{Symbols: []string{"BNBBTC", "LTCBTC"}, Type: TickerMini})
+			},
+			want: []*Ticker24{
+				{
+					Symbol:    "BNBBTC",
+					LastPrice: 4.000002,
+					OpenPrice: 99,
+					HighPrice: 100,
+					LowPrice:  0.1,
+					Volume:    8913.3,
+					OpenTime:  klineOpen,
+					CloseTime: klineClose,
+					FirstID:   28385,
+					LastID:    28460,
+					Count:     76,
+				},
+				{
+					Symbol:    "LTCBTC",
+					LastPrice: 0.0041,
+					OpenPrice: 0.004,
+					HighPrice: 0.0042,
+					LowPrice:  0.0039,
+					Volume:    1200.5,
+					OpenTime:  klineOpen,
+					CloseTime: klineClose,
+					FirstID:   10,
+					LastID:    12,
+					Count:     3,
+				},
+			},
+		},
+		{
+			name:    "TickerAllPrices",
+			method:  "GET",
+			path:    "/api/v1/ticker/allPrices",
+			fixture: "ticker_all_prices.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.TickerAllPrices()
+			},
+			want: []*PriceTicker{
+				{Symbol: "LTCBTC", Price: 4.000002},
+				{Symbol: "ETHBTC", Price: 0.079466},
+			},
+		},
+		{
+			name:    "TickerAllPrices error",
+			method:  "GET",
+			path:    "/api/v1/ticker/allPrices",
+			fixture: "error_invalid_symbol.json",
+			status:  http.StatusBadRequest,
+			call: func(as *apiService) (interface{}, error) {
+				return as.TickerAllPrices()
+			},
+			wantErr: &Error{Code: -1121, Message: "Invalid symbol."},
+		},
+		{
+			name:    "TickerAllBooks",
+			method:  "GET",
+			path:    "/api/v1/ticker/allBookTickers",
+			fixture: "ticker_all_book_tickers.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.TickerAllBooks()
+			},
+			want: []*BookTicker{
+				{Symbol: "LTCBTC", BidPrice: 4, BidQty: 431, AskPrice: 4.000002, AskQty: 9},
+				{Symbol: "ETHBTC", BidPrice: 0.079467, BidQty: 9, AskPrice: 100000, AskQty: 1000},
+			},
+		},
+	})
+}
+
+// fixtureKline is kline of klines.json.
+var fixtureKline = &Kline{
+	OpenTime:                 timeFromUnixMillis(1499040000000),
+	Open:                     0.0163479,
+	High:                     0.8,
+	Low:                      0.015758,
+	Close:                    0.015771,
+	Volume:                   148976.11427815,
+	CloseTime:                timeFromUnixMillis(1499644799999),
+	QuoteAssetVolume:         2434.19055334,
+	NumberOfTrades:           308,
+	TakerBuyBaseAssetVolume:  1756.87402397,
+	TakerBuyQuoteAssetVolume: 28.46694368,
+
+	RawOpen:                     "0.01634790",
+	RawHigh:                     "0.80000000",
+	RawLow:                      "0.01575800",
+	RawClose:                    "0.01577100",
+	RawVolume:                   "148976.11427815",
+	RawQuoteAssetVolume:         "2434.19055334",
+	RawTakerBuyBaseAssetVolume:  "1756.87402397",
+	RawTakerBuyQuoteAssetVolume: "28.46694368",
+}
+
+// checkExchangeInfoFixture checks exchange info of exchange_info.json,
+// symbol filters are checked separately as they are of anonymous type.
+func checkExchangeInfoFixture(t *testing.T, got interface{}) {
+	info := got.(*ExchangeInfo)
+	if len(info.Symbols) != 1 {
+		t.Fatalf("got %d symbols, want 1", len(info.Symbols))
+	}
+	filters := info.Symbols[0].Filters
+	info.Symbols[0].Filters = nil
+	checkFields(t, info, &ExchangeInfo{
+		TimeZone:   "UTC",
+		ServerTime: 1565246363776,
+		RateLimits: []RateLimit{
+			{RateLimitType: RateLimitRequestWeight, Interval: RateLimitMinute, IntervalNum: 1, Limit: 1200},
+			{RateLimitType: RateLimitOrders, Interval: RateLimitSecond, IntervalNum: 10, Limit: 100},
+		},
+		ExchangeFilters: []interface{}{},
+		Symbols: []Symbol{
+			{
+				Asset:              "ETHBTC",
+				Status:             "TRADING",
+				BaseAsset:          "ETH",
+				BaseAssetPrecision: 8,
+				QuoteAsset:         "BTC",
+				QuotePrecision:     8,
+				OrderTypes:         []string{"LIMIT", "LIMIT_MAKER", "MARKET", "STOP_LOSS_LIMIT", "TAKE_PROFIT_LIMIT"},
+				IcebergAllowed:     true,
+			},
+		},
+	})
+
+	wantFilters := []struct {
+		filterType                                string
+		minPrice, maxPrice, stepSize, minNotional float64
+	}{
+		{filterType: "PRICE_FILTER", minPrice: 0.000001, maxPrice: 100000},
+		{filterType: "LOT_SIZE", stepSize: 0.001},
+		{filterType: "MIN_NOTIONAL", minNotional: 0.001},
+	}
+	if len(filters) != len(wantFilters) {
+		t.Fatalf("got %d filters, want %d", len(filters), len(wantFilters))
+	}
+	for i, want := range wantFilters {
+		f := filters[i]
+		if f.FilterType != want.filterType || f.MinPrice != want.minPrice || f.MaxPrice != want.maxPrice ||
+			f.StepSize != want.stepSize || f.MinNotional != want.minNotional {
+			t.Errorf("Filters[%d] = %+v, want %+v", i, f, want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package binance
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+type rawListStatus struct {
+	Symbol            string `json:"symbol"`
+	OrderListID       int64  `json:"orderListId"`
+	ContingencyType   string `json:"contingencyType"`
+	ListStatusType    string `json:"listStatusType"`
+	ListOrderStatus   string `json:"listOrderStatus"`
+	ListClientOrderID string `json:"listClientOrderId"`
+	TransactionTime   int64  `json:"transactionTime"`
+	Orders            []struct {
+		Symbol        string     `json:"symbol"`
+		OrderID       lenientInt `json:"orderId"`
+		ClientOrderID string     `json:"clientOrderId"`
+	} `json:"orders"`
+}
+
+func (as *apiService) OpenOCOOrders(oor OpenOCOOrdersRequest) ([]*ListStatus, error) {
+	params := make(map[string]string)
+	params["timestamp"] = strconv.FormatInt(unixMillis(oor.Timestamp), 10)
+	if oor.RecvWindow != 0 {
+		params["recvWindow"] = strconv.FormatInt(recvWindow(oor.RecvWindow), 10)
+	}
+	return as.orderLists("api/v3/openOrderList", params)
+}
+
+func (as *apiService) AllOCOOrders(aor AllOCOOrdersRequest) ([]*ListStatus, error) {
+	params := make(map[string]string)
+	params["timestamp"] = strconv.FormatInt(unixMillis(aor.Timestamp), 10)
+	if aor.FromID != 0 {
+		params["fromId"] = strconv.FormatInt(aor.FromID, 10)
+	}
+	if !aor.StartTime.IsZero() {
+		params["startTime"] = strconv.FormatInt(unixMillis(aor.StartTime), 10)
+	}
+	if !aor.EndTime.IsZero() {
+		params["endTime"] = strconv.FormatInt(unixMillis(aor.EndTime), 10)
+	}
+	if aor.Limit != 0 {
+		params["limit"] = strconv.Itoa(aor.Limit)
+	}
+	if aor.RecvWindow != 0 {
+		params["recvWindow"] = strconv.FormatInt(recvWindow(aor.RecvWindow), 10)
+	}
+	return as.orderLists("api/v3/allOrderList", params)
+}
+
+func (as *apiService) orderLists(endpoint string, params map[string]string) ([]*ListStatus, error) {
+	res, err := as.request("GET", endpoint, params, true, true)
+	if err != nil {
+		return nil, err
+	}
+	textRes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read response from %s", endpoint)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return nil, as.handleError(textRes)
+	}
+
+	rawLists := []*rawListStatus{}
+	if err := json.Unmarshal(textRes, &rawLists); err != nil {
+		return nil, errors.Wrap(err, "rawListStatus unmarshal failed")
+	}
+
+	var lsc []*ListStatus
+	for _, rl := range rawLists {
+		ls := &ListStatus{
+			Symbol:            rl.Symbol,
+			OrderListID:       rl.OrderListID,
+			ContingencyType:   rl.ContingencyType,
+			ListStatusType:    rl.ListStatusType,
+			ListOrderStatus:   rl.ListOrderStatus,
+			ListClientOrderID: rl.ListClientOrderID,
+			TransactionTime:   timeFromUnixMillis(rl.TransactionTime),
+		}
+		for _, o := range rl.Orders {
+			ls.Orders = append(ls.Orders, &ListStatusOrder{
+				Symbol:        o.Symbol,
+				OrderID:       int64(o.OrderID),
+				ClientOrderID: o.ClientOrderID,
+			})
+		}
+		lsc = append(lsc, ls)
+	}
+	return lsc, nil
+}
@@ -0,0 +1,159 @@
+package binance
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// streamRequestTimeout limits waiting for response to stream control request.
+const streamRequestTimeout = 10 * time.Second
+
+type streamClient struct {
+	as       *apiService
+	conn     *wsConn
+	messages chan *StreamMessage
+	done     chan struct{}
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	lastID  int64
+	pending map[int64]chan *streamResponse
+}
+
+type streamRequest struct {
+	Method string   `json:"method"`
+	Params []string `json:"params,omitempty"`
+	ID     int64    `json:"id"`
+}
+
+type streamResponse struct {
+	ID     *int64          `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *Error          `json:"error"`
+	Stream string          `json:"stream"`
+	Data   json.RawMessage `json:"data"`
+}
+
+func (as *apiService) NewStreamClient() (StreamClient, error) {
+	as = as.streamService()
+	url := "wss://stream.binance.com:9443/stream"
+
+	c, err := as.wsDial(url)
+	if err != nil {
+		as.stopStream()
+		return nil, err
+	}
+	sc := &streamClient{
+		as:       as,
+		conn:     c,
+		messages: make(chan *StreamMessage),
+		pending:  make(map[int64]chan *streamResponse),
+	}
+	sc.done = as.wsServeConn(c, url, nil, sc.handle)
+	return sc, nil
+}
+
+func (sc *streamClient) handle(message []byte) error {
+	res := &streamResponse{}
+	if err := json.Unmarshal(message, res); err != nil {
+		return errors.Wrap(err, "streamResponse unmarshal failed")
+	}
+	if res.ID != nil {
+		sc.mu.Lock()
+		resCh, ok := sc.pending[*res.ID]
+		delete(sc.pending, *res.ID)
+		sc.mu.Unlock()
+		if ok {
+			resCh <- res
+		}
+		return nil
+	}
+	select {
+	case sc.messages <- &StreamMessage{Stream: res.Stream, Data: res.Data}:
+	case <-sc.as.Ctx.Done():
+	}
+	return nil
+}
+
+// call sends control request and waits for response with the same id.
+func (sc *streamClient) call(method string, params []string) (json.RawMessage, error) {
+	sc.mu.Lock()
+	sc.lastID++
+	id := sc.lastID
+	resCh := make(chan *streamResponse, 1)
+	sc.pending[id] = resCh
+	sc.mu.Unlock()
+
+	sc.writeMu.Lock()
+	err := sc.conn.WriteJSON(&streamRequest{
+		Method: method,
+		Params: params,
+		ID:     id,
+	})
+	sc.writeMu.Unlock()
+	if err != nil {
+		sc.forget(id)
+		return nil, errors.Wrap(err, "stream request write failed")
+	}
+
+	timer := time.NewTimer(streamRequestTimeout)
+	defer timer.Stop()
+	select {
+	case res := <-resCh:
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		return res.Result, nil
+	case <-sc.done:
+		return nil, errors.New("stream connection closed")
+	case <-timer.C:
+		sc.forget(id)
+		return nil, errors.Errorf("stream request %s timed out", method)
+	}
+}
+
+func (sc *streamClient) forget(id int64) {
+	sc.mu.Lock()
+	delete(sc.pending, id)
+	sc.mu.Unlock()
+}
+
+func (sc *streamClient) Subscribe(streams ...string) error {
+	if _, err := sc.call("SUBSCRIBE", streams); err != nil {
+		return err
+	}
+	sc.as.streams.subscribed(sc.done, streams, nil)
+	return nil
+}
+
+func (sc *streamClient) Unsubscribe(streams ...string) error {
+	if _, err := sc.call("UNSUBSCRIBE", streams); err != nil {
+		return err
+	}
+	sc.as.streams.subscribed(sc.done, nil, streams)
+	return nil
+}
+
+func (sc *streamClient) ListSubscriptions() ([]string, error) {
+	result, err := sc.call("LIST_SUBSCRIPTIONS", nil)
+	if err != nil {
+		return nil, err
+	}
+	var streams []string
+	if err := json.Unmarshal(result, &streams); err != nil {
+		return nil, errors.Wrap(err, "subscriptions unmarshal failed")
+	}
+	return streams, nil
+}
+
+func (sc *streamClient) Messages() <-chan *StreamMessage {
+	return sc.messages
+}
+
+func (sc *streamClient) Done() <-chan struct{} {
+	return sc.done
+}
@@ -0,0 +1,161 @@
+package binance
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+func (as *apiService) SubAccountList(salr SubAccountListRequest) ([]*SubAccount, error) {
+	params := make(map[string]string)
+	params["timestamp"] = strconv.FormatInt(unixMillis(salr.Timestamp), 10)
+	if salr.Email != "" {
+		params["email"] = salr.Email
+	}
+	if salr.IsFreeze != nil {
+		params["isFreeze"] = strconv.FormatBool(*salr.IsFreeze)
+	}
+	if salr.Page != 0 {
+		params["page"] = strconv.Itoa(salr.Page)
+	}
+	if salr.Limit != 0 {
+		params["limit"] = strconv.Itoa(salr.Limit)
+	}
+	if salr.RecvWindow != 0 {
+		params["recvWindow"] = strconv.FormatInt(recvWindow(salr.RecvWindow), 10)
+	}
+
+	res, err := as.request("GET", "sapi/v1/sub-account/list", params, true, true)
+	if err != nil {
+		return nil, err
+	}
+	textRes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read response from sub-account/list.get")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return nil, as.handleError(textRes)
+	}
+
+	rawList := struct {
+		SubAccounts []struct {
+			Email      string  `json:"email"`
+			IsFreeze   bool    `json:"isFreeze"`
+			CreateTime float64 `json:"createTime"`
+		} `json:"subAccounts"`
+	}{}
+	if err := json.Unmarshal(textRes, &rawList); err != nil {
+		return nil, errors.Wrap(err, "rawSubAccounts unmarshal failed")
+	}
+
+	var sac []*SubAccount
+	for _, rsa := range rawList.SubAccounts {
+		t, err := timeFromUnixTimestampFloat(rsa.CreateTime)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot parse SubAccount.CreateTime")
+		}
+		sac = append(sac, &SubAccount{
+			Email:      rsa.Email,
+			IsFreeze:   rsa.IsFreeze,
+			CreateTime: t,
+		})
+	}
+	return sac, nil
+}
+
+func (as *apiService) SubAccountTransfer(satr SubAccountTransferRequest) (*SubAccountTransferResult, error) {
+	params := make(map[string]string)
+	params["asset"] = satr.Asset
+	params["amount"] = strconv.FormatFloat(satr.Amount, 'f', -1, 64)
+	params["fromAccountType"] = "SPOT"
+	params["toAccountType"] = "SPOT"
+	params["timestamp"] = strconv.FormatInt(unixMillis(satr.Timestamp), 10)
+	if satr.FromEmail != "" {
+		params["fromEmail"] = satr.FromEmail
+	}
+	if satr.ToEmail != "" {
+		params["toEmail"] = satr.ToEmail
+	}
+	if satr.FromAccountType != "" {
+		params["fromAccountType"] = satr.FromAccountType
+	}
+	if satr.ToAccountType != "" {
+		params["toAccountType"] = satr.ToAccountType
+	}
+	if satr.RecvWindow != 0 {
+		params["recvWindow"] = strconv.FormatInt(recvWindow(satr.RecvWindow), 10)
+	}
+
+	res, err := as.request("POST", "sapi/v1/sub-account/universalTransfer", params, true, true)
+	if err != nil {
+		return nil, err
+	}
+	textRes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read response from sub-account/universalTransfer.post")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return nil, as.handleError(textRes)
+	}
+
+	rawResult := struct {
+		TranID int64 `json:"tranId"`
+	}{}
+	if err := json.Unmarshal(textRes, &rawResult); err != nil {
+		return nil, errors.Wrap(err, "rawSubAccountTransfer unmarshal failed")
+	}
+
+	return &SubAccountTransferResult{
+		TranID: rawResult.TranID,
+	}, nil
+}
+
+func (as *apiService) SubAccountAssets(saar SubAccountAssetsRequest) ([]*Balance, error) {
+	params := make(map[string]string)
+	params["email"] = saar.Email
+	params["timestamp"] = strconv.FormatInt(unixMillis(saar.Timestamp), 10)
+	if saar.RecvWindow != 0 {
+		params["recvWindow"] = strconv.FormatInt(recvWindow(saar.RecvWindow), 10)
+	}
+
+	res, err := as.request("GET", "sapi/v3/sub-account/assets", params, true, true)
+	if err != nil {
+		return nil, err
+	}
+	textRes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read response from sub-account/assets.get")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return nil, as.handleError(textRes)
+	}
+
+	rawAssets := struct {
+		Balances []struct {
+			Asset  string  `json:"asset"`
+			Free   float64 `json:"free"`
+			Locked float64 `json:"locked"`
+		} `json:"balances"`
+	}{}
+	if err := json.Unmarshal(textRes, &rawAssets); err != nil {
+		return nil, errors.Wrap(err, "rawSubAccountAssets unmarshal failed")
+	}
+
+	var bc []*Balance
+	for _, b := range rawAssets.Balances {
+		bc = append(bc, &Balance{
+			Asset:  b.Asset,
+			Free:   b.Free,
+			Locked: b.Locked,
+		})
+	}
+	return bc, nil
+}
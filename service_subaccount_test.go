@@ -0,0 +1,53 @@
+package binance
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSubAccountFixtures(t *testing.T) {
+	now := time.Now()
+	testRESTFixtures(t, []restFixture{
+		{
+			name:    "SubAccountList",
+			method:  "GET",
+			path:    "/sapi/v1/sub-account/list",
+			fixture: "sub_account_list.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.SubAccountList(SubAccountListRequest{Timestamp: now})
+			},
+			want: []*SubAccount{
+				{Email: "testsub@gmail.com", IsFreeze: false, CreateTime: timeFromUnixMillis(1544433328000)},
+				{Email: "virtual@oxebmvfonoemail.com", IsFreeze: true, CreateTime: timeFromUnixMillis(1544433328000)},
+			},
+		},
+		{
+			name:    "SubAccountTransfer",
+			method:  "POST",
+			path:    "/sapi/v1/sub-account/universalTransfer",
+			fixture: "sub_account_transfer.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.SubAccountTransfer(SubAccountTransferRequest{
+					ToEmail:   "testsub@gmail.com",
+					Asset:     "USDT",
+					Amount:    1,
+					Timestamp: now,
+				})
+			},
+			want: &SubAccountTransferResult{TranID: 11945860693},
+		},
+		{
+			name:    "SubAccountAssets",
+			method:  "GET",
+			path:    "/sapi/v3/sub-account/assets",
+			fixture: "sub_account_assets.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.SubAccountAssets(SubAccountAssetsRequest{Email: "testsub@gmail.com", Timestamp: now})
+			},
+			want: []*Balance{
+				{Asset: "ADA", Free: 10000},
+				{Asset: "BNB", Free: 10003, Locked: 0.5},
+			},
+		},
+	})
+}
@@ -3,15 +3,33 @@ package binance
 import (
 	"encoding/json"
 	"io/ioutil"
-	"log"
+	"time"
 
+	"github.com/go-kit/kit/log/level"
 	"github.com/pkg/errors"
 )
 
 func (as *apiService) StartUserDataStream() (*Stream, error) {
+	return as.startUserDataStream(&Stream{})
+}
+
+func (as *apiService) StartMarginUserDataStream() (*Stream, error) {
+	return as.startUserDataStream(&Stream{Margin: true})
+}
+
+func (as *apiService) StartIsolatedMarginUserDataStream(symbol string) (*Stream, error) {
+	return as.startUserDataStream(&Stream{Symbol: NormalizeSymbol(symbol)})
+}
+
+// startUserDataStream creates listen key of the kind of stream s and returns
+// copy of s with it.
+func (as *apiService) startUserDataStream(s *Stream) (*Stream, error) {
 	params := make(map[string]string)
+	if s.Symbol != "" {
+		params["symbol"] = s.Symbol
+	}
 
-	res, err := as.request("POST", "api/v1/userDataStream", params, true, false)
+	res, err := as.request("POST", s.endpoint(), params, true, false)
 	if err != nil {
 		return nil, err
 	}
@@ -21,22 +39,25 @@ func (as *apiService) StartUserDataStream() (*Stream, error) {
 	}
 	defer res.Body.Close()
 
-	log.Println(string(textRes))
+	level.Debug(as.Logger).Log("userDataStream", string(textRes))
 	if res.StatusCode != 200 {
 		return nil, as.handleError(textRes)
 	}
 
-	var s Stream
-	if err := json.Unmarshal(textRes, &s); err != nil {
+	fresh := Stream{Margin: s.Margin, Symbol: s.Symbol}
+	if err := json.Unmarshal(textRes, &fresh); err != nil {
 		return nil, errors.Wrap(err, "stream unmarshal failed")
 	}
-	return &s, nil
+	return &fresh, nil
 }
 func (as *apiService) KeepAliveUserDataStream(s *Stream) error {
 	params := make(map[string]string)
 	params["listenKey"] = s.ListenKey
+	if s.Symbol != "" {
+		params["symbol"] = s.Symbol
+	}
 
-	res, err := as.request("PUT", "api/v1/userDataStream", params, true, false)
+	res, err := as.request("PUT", s.endpoint(), params, true, false)
 	if err != nil {
 		return err
 	}
@@ -54,8 +75,11 @@ func (as *apiService) KeepAliveUserDataStream(s *Stream) error {
 func (as *apiService) CloseUserDataStream(s *Stream) error {
 	params := make(map[string]string)
 	params["listenKey"] = s.ListenKey
+	if s.Symbol != "" {
+		params["symbol"] = s.Symbol
+	}
 
-	res, err := as.request("DELETE", "api/v1/userDataStream", params, true, false)
+	res, err := as.request("DELETE", s.endpoint(), params, true, false)
 	if err != nil {
 		return err
 	}
@@ -70,3 +94,128 @@ func (as *apiService) CloseUserDataStream(s *Stream) error {
 	}
 	return nil
 }
+
+const (
+	// userDataKeepAlivePeriod is well within 60 minutes listen key validity.
+	userDataKeepAlivePeriod = 30 * time.Minute
+	// userDataMaxBackoff limits delay between reconnection attempts.
+	userDataMaxBackoff = time.Minute
+)
+
+func (as *apiService) UserDataStream() (chan *AccountEvent, chan struct{}, error) {
+	return as.userDataStream(&Stream{})
+}
+
+func (as *apiService) MarginUserDataStream() (chan *AccountEvent, chan struct{}, error) {
+	return as.userDataStream(&Stream{Margin: true})
+}
+
+func (as *apiService) IsolatedMarginUserDataStream(symbol string) (chan *AccountEvent, chan struct{}, error) {
+	return as.userDataStream(&Stream{Symbol: NormalizeSymbol(symbol)})
+}
+
+// userDataStream runs user data stream of the kind of stream kind, see
+// UserDataStream.
+func (as *apiService) userDataStream(kind *Stream) (chan *AccountEvent, chan struct{}, error) {
+	as = as.streamService()
+	s, err := as.startUserDataStream(kind)
+	if err != nil {
+		as.stopStream()
+		return nil, nil, err
+	}
+	ech, wsDone, err := as.UserDataWebsocket(UserDataWebsocketRequest{ListenKey: s.ListenKey})
+	if err != nil {
+		as.stopStream()
+		return nil, nil, err
+	}
+
+	aech := make(chan *AccountEvent)
+	done := make(chan struct{})
+	as.streams.addCloser(done, as.cancelStream)
+	go func() {
+		defer close(done)
+		defer as.streams.remove(done)
+		defer as.stopStream()
+		reconnects := 0
+		expired := false
+		keepAlive := time.NewTicker(userDataKeepAlivePeriod)
+		defer keepAlive.Stop()
+		for {
+			select {
+			case ae := <-ech:
+				select {
+				case aech <- ae:
+				case <-as.Ctx.Done():
+					return
+				}
+				if ae.ListenKeyExpired != nil {
+					// Binance doesn't necessarily close the connection, so
+					// it's closed here to reconnect with fresh listen key.
+					expired = true
+					as.streams.close(wsDone)
+				}
+			case <-keepAlive.C:
+				if err := as.KeepAliveUserDataStream(s); err != nil {
+					level.Warn(as.Logger).Log("userDataStream", "keepalive failed", "err", err)
+				}
+			case <-wsDone:
+				s, ech, wsDone = as.reconnectUserDataStream(s, expired)
+				expired = false
+				if s == nil {
+					return
+				}
+				reconnects++
+				as.streams.addReconnects(wsDone, reconnects)
+			case <-as.Ctx.Done():
+				return
+			}
+		}
+	}()
+	return aech, done, nil
+}
+
+// reconnectUserDataStream reconnects websocket of stream s, using fresh
+// listen key if s expired, which is known up front when listenKeyExpired
+// event was received. It retries with backoff and returns nil stream only
+// once service context is cancelled.
+func (as *apiService) reconnectUserDataStream(s *Stream, expired bool) (*Stream, chan *AccountEvent, chan struct{}) {
+	backoff := time.Second
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(backoff)
+			select {
+			case <-timer.C:
+			case <-as.Ctx.Done():
+				timer.Stop()
+				return nil, nil, nil
+			}
+			if backoff *= 2; backoff > userDataMaxBackoff {
+				backoff = userDataMaxBackoff
+			}
+		}
+		if as.Ctx.Err() != nil {
+			return nil, nil, nil
+		}
+		if !expired {
+			if err := as.KeepAliveUserDataStream(s); err != nil {
+				level.Info(as.Logger).Log("userDataStream", "renewing listen key", "err", err)
+				expired = true
+			}
+		}
+		if expired {
+			// connecting with expired key would only fail again
+			fresh, err := as.startUserDataStream(s)
+			if err != nil {
+				level.Warn(as.Logger).Log("userDataStream", "listen key renewal failed", "err", err)
+				continue
+			}
+			s = fresh
+			expired = false
+		}
+		ech, done, err := as.UserDataWebsocket(UserDataWebsocketRequest{ListenKey: s.ListenKey})
+		if err == nil {
+			return s, ech, done
+		}
+		level.Warn(as.Logger).Log("userDataStream", "reconnect failed", "err", err)
+	}
+}
@@ -0,0 +1,44 @@
+package binance
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+func TestReconnectUserDataStreamRenewalFailure(t *testing.T) {
+	var renewals int32
+	as := newTestStreamService(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/userDataStream":
+			if r.Method != "POST" {
+				t.Errorf("unexpected %s of listen key", r.Method)
+			}
+			if atomic.AddInt32(&renewals, 1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				fmt.Fprint(w, `{"code":-1001,"msg":"Internal error; unable to process your request."}`)
+				return
+			}
+			fmt.Fprint(w, `{"listenKey":"fresh"}`)
+		case "/ws/fresh":
+			if c := upgradeTestWS(t, w, r); c != nil {
+				holdTestWS(c)
+			}
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	s, _, done := as.reconnectUserDataStream(&Stream{ListenKey: "expired"}, true)
+	if s == nil {
+		t.Fatal("stream not reconnected")
+	}
+	defer as.CloseStream(done)
+	if s.ListenKey != "fresh" {
+		t.Errorf("reconnected with listen key %q, want fresh", s.ListenKey)
+	}
+	if n := atomic.LoadInt32(&renewals); n != 2 {
+		t.Errorf("listen key requested %d times, want 2", n)
+	}
+}
@@ -0,0 +1,289 @@
+package binance
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func (as *apiService) AccountSnapshot(asr AccountSnapshotRequest) (*AccountSnapshot, error) {
+	params := make(map[string]string)
+	params["type"] = string(asr.Type)
+	params["timestamp"] = strconv.FormatInt(unixMillis(asr.Timestamp), 10)
+	if !asr.StartTime.IsZero() {
+		params["startTime"] = strconv.FormatInt(unixMillis(asr.StartTime), 10)
+	}
+	if !asr.EndTime.IsZero() {
+		params["endTime"] = strconv.FormatInt(unixMillis(asr.EndTime), 10)
+	}
+	if asr.Limit != 0 {
+		params["limit"] = strconv.Itoa(asr.Limit)
+	}
+	if asr.RecvWindow != 0 {
+		params["recvWindow"] = strconv.FormatInt(recvWindow(asr.RecvWindow), 10)
+	}
+
+	res, err := as.request("GET", "sapi/v1/accountSnapshot", params, true, true)
+	if err != nil {
+		return nil, err
+	}
+	textRes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read response from accountSnapshot.get")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return nil, as.handleError(textRes)
+	}
+
+	rawSnapshot := struct {
+		SnapshotVos []struct {
+			Type       string `json:"type"`
+			UpdateTime int64  `json:"updateTime"`
+			Data       struct {
+				TotalAssetOfBTC float64 `json:"totalAssetOfBtc,string"`
+				Balances        []struct {
+					Asset  string  `json:"asset"`
+					Free   float64 `json:"free,string"`
+					Locked float64 `json:"locked,string"`
+				} `json:"balances"`
+				MarginLevel         float64 `json:"marginLevel,string"`
+				TotalLiabilityOfBTC float64 `json:"totalLiabilityOfBtc,string"`
+				TotalNetAssetOfBTC  float64 `json:"totalNetAssetOfBtc,string"`
+				UserAssets          []struct {
+					Asset    string  `json:"asset"`
+					Borrowed float64 `json:"borrowed,string"`
+					Free     float64 `json:"free,string"`
+					Interest float64 `json:"interest,string"`
+					Locked   float64 `json:"locked,string"`
+					NetAsset float64 `json:"netAsset,string"`
+				} `json:"userAssets"`
+				Assets []struct {
+					Asset         string  `json:"asset"`
+					MarginBalance float64 `json:"marginBalance,string"`
+					WalletBalance float64 `json:"walletBalance,string"`
+				} `json:"assets"`
+				Position []struct {
+					Symbol           string  `json:"symbol"`
+					EntryPrice       float64 `json:"entryPrice,string"`
+					MarkPrice        float64 `json:"markPrice,string"`
+					PositionAmt      float64 `json:"positionAmt,string"`
+					UnRealizedProfit float64 `json:"unRealizedProfit,string"`
+				} `json:"position"`
+			} `json:"data"`
+		} `json:"snapshotVos"`
+	}{}
+	if err := json.Unmarshal(textRes, &rawSnapshot); err != nil {
+		return nil, errors.Wrap(err, "rawSnapshot unmarshal failed")
+	}
+
+	snapshot := &AccountSnapshot{}
+	for _, rs := range rawSnapshot.SnapshotVos {
+		ds := &DailySnapshot{
+			Type:                rs.Type,
+			UpdateTime:          timeFromUnixMillis(rs.UpdateTime),
+			TotalAssetOfBTC:     rs.Data.TotalAssetOfBTC,
+			MarginLevel:         rs.Data.MarginLevel,
+			TotalLiabilityOfBTC: rs.Data.TotalLiabilityOfBTC,
+			TotalNetAssetOfBTC:  rs.Data.TotalNetAssetOfBTC,
+		}
+		for _, b := range rs.Data.Balances {
+			ds.Balances = append(ds.Balances, &Balance{
+				Asset:  b.Asset,
+				Free:   b.Free,
+				Locked: b.Locked,
+			})
+		}
+		for _, a := range rs.Data.UserAssets {
+			ds.UserAssets = append(ds.UserAssets, &MarginSnapshotAsset{
+				Asset:    a.Asset,
+				Borrowed: a.Borrowed,
+				Free:     a.Free,
+				Interest: a.Interest,
+				Locked:   a.Locked,
+				NetAsset: a.NetAsset,
+			})
+		}
+		for _, a := range rs.Data.Assets {
+			ds.FuturesAssets = append(ds.FuturesAssets, &FuturesSnapshotAsset{
+				Asset:         a.Asset,
+				MarginBalance: a.MarginBalance,
+				WalletBalance: a.WalletBalance,
+			})
+		}
+		for _, p := range rs.Data.Position {
+			ds.Positions = append(ds.Positions, &FuturesSnapshotPosition{
+				Symbol:           p.Symbol,
+				EntryPrice:       p.EntryPrice,
+				MarkPrice:        p.MarkPrice,
+				PositionAmt:      p.PositionAmt,
+				UnRealizedProfit: p.UnRealizedProfit,
+			})
+		}
+		snapshot.Snapshots = append(snapshot.Snapshots, ds)
+	}
+	return snapshot, nil
+}
+
+func (as *apiService) UniversalTransfer(utr UniversalTransferRequest) (*TransferResult, error) {
+	params := make(map[string]string)
+	params["type"] = string(utr.Type)
+	params["asset"] = utr.Asset
+	params["amount"] = strconv.FormatFloat(utr.Amount, 'f', -1, 64)
+	params["timestamp"] = strconv.FormatInt(unixMillis(utr.Timestamp), 10)
+	if utr.FromSymbol != "" {
+		params["fromSymbol"] = NormalizeSymbol(utr.FromSymbol)
+	}
+	if utr.ToSymbol != "" {
+		params["toSymbol"] = NormalizeSymbol(utr.ToSymbol)
+	}
+	if utr.RecvWindow != 0 {
+		params["recvWindow"] = strconv.FormatInt(recvWindow(utr.RecvWindow), 10)
+	}
+
+	res, err := as.request("POST", "sapi/v1/asset/transfer", params, true, true)
+	if err != nil {
+		return nil, err
+	}
+	textRes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read response from asset/transfer.post")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return nil, as.handleError(textRes)
+	}
+
+	rawResult := struct {
+		TranID int64 `json:"tranId"`
+	}{}
+	if err := json.Unmarshal(textRes, &rawResult); err != nil {
+		return nil, errors.Wrap(err, "rawTransfer unmarshal failed")
+	}
+	return &TransferResult{
+		TranID: rawResult.TranID,
+	}, nil
+}
+
+func (as *apiService) FundingAssets(far FundingAssetsRequest) ([]*FundingAsset, error) {
+	return as.fundingAssets("sapi/v1/asset/get-funding-asset", far)
+}
+
+func (as *apiService) UserAssets(far FundingAssetsRequest) ([]*FundingAsset, error) {
+	return as.fundingAssets("sapi/v3/asset/getUserAsset", far)
+}
+
+// fundingAssets requests balances of endpoint, both funding and user assets
+// share request and response format.
+func (as *apiService) fundingAssets(endpoint string, far FundingAssetsRequest) ([]*FundingAsset, error) {
+	params := make(map[string]string)
+	params["timestamp"] = strconv.FormatInt(unixMillis(far.Timestamp), 10)
+	if far.Asset != "" {
+		params["asset"] = far.Asset
+	}
+	if far.NeedBTCValuation {
+		params["needBtcValuation"] = "true"
+	}
+	if far.RecvWindow != 0 {
+		params["recvWindow"] = strconv.FormatInt(recvWindow(far.RecvWindow), 10)
+	}
+
+	res, err := as.request("POST", endpoint, params, true, true)
+	if err != nil {
+		return nil, err
+	}
+	textRes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read response from %s.post", endpoint)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return nil, as.handleError(textRes)
+	}
+
+	rawAssets := []struct {
+		Asset        string  `json:"asset"`
+		Free         float64 `json:"free,string"`
+		Locked       float64 `json:"locked,string"`
+		Freeze       float64 `json:"freeze,string"`
+		Withdrawing  float64 `json:"withdrawing,string"`
+		BTCValuation float64 `json:"btcValuation,string"`
+	}{}
+	if err := json.Unmarshal(textRes, &rawAssets); err != nil {
+		return nil, errors.Wrap(err, "rawAssets unmarshal failed")
+	}
+
+	var assets []*FundingAsset
+	for _, a := range rawAssets {
+		assets = append(assets, &FundingAsset{
+			Asset:        a.Asset,
+			Free:         a.Free,
+			Locked:       a.Locked,
+			Freeze:       a.Freeze,
+			Withdrawing:  a.Withdrawing,
+			BTCValuation: a.BTCValuation,
+		})
+	}
+	return assets, nil
+}
+
+func (as *apiService) DustEstimate() (*DustEstimate, error) {
+	params := make(map[string]string)
+	params["timestamp"] = strconv.FormatInt(unixMillis(time.Now()), 10)
+
+	res, err := as.request("POST", "sapi/v1/asset/dust-btc", params, true, true)
+	if err != nil {
+		return nil, err
+	}
+	textRes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read response from asset/dust-btc.post")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return nil, as.handleError(textRes)
+	}
+
+	rawEstimate := struct {
+		Details []struct {
+			Asset            string  `json:"asset"`
+			AssetFullName    string  `json:"assetFullName"`
+			AmountFree       float64 `json:"amountFree,string"`
+			ToBTC            float64 `json:"toBTC,string"`
+			ToBNB            float64 `json:"toBNB,string"`
+			ToBNBOffExchange float64 `json:"toBNBOffExchange,string"`
+			Exchange         float64 `json:"exchange,string"`
+		} `json:"details"`
+		TotalTransferBTC   float64 `json:"totalTransferBtc,string"`
+		TotalTransferBNB   float64 `json:"totalTransferBNB,string"`
+		DribbletPercentage float64 `json:"dribbletPercentage,string"`
+	}{}
+	if err := json.Unmarshal(textRes, &rawEstimate); err != nil {
+		return nil, errors.Wrap(err, "rawEstimate unmarshal failed")
+	}
+
+	de := &DustEstimate{
+		TotalTransferBTC:   rawEstimate.TotalTransferBTC,
+		TotalTransferBNB:   rawEstimate.TotalTransferBNB,
+		DribbletPercentage: rawEstimate.DribbletPercentage,
+	}
+	for _, d := range rawEstimate.Details {
+		de.Details = append(de.Details, &DustAsset{
+			Asset:            d.Asset,
+			AssetFullName:    d.AssetFullName,
+			AmountFree:       d.AmountFree,
+			ToBTC:            d.ToBTC,
+			ToBNB:            d.ToBNB,
+			ToBNBOffExchange: d.ToBNBOffExchange,
+			Exchange:         d.Exchange,
+		})
+	}
+	return de, nil
+}
@@ -0,0 +1,153 @@
+package binance
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWalletFixtures(t *testing.T) {
+	now := time.Now()
+	snapshotTime := timeFromUnixMillis(1576281599000)
+	testRESTFixtures(t, []restFixture{
+		{
+			name:    "AccountSnapshot spot",
+			method:  "GET",
+			path:    "/sapi/v1/accountSnapshot",
+			fixture: "account_snapshot_spot.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.AccountSnapshot(AccountSnapshotRequest{Type: SnapshotSpot, Timestamp: now})
+			},
+			want: &AccountSnapshot{
+				Snapshots: []*DailySnapshot{
+					{
+						Type:            "spot",
+						UpdateTime:      snapshotTime,
+						TotalAssetOfBTC: 0.099427,
+						Balances: []*Balance{
+							{Asset: "BTC", Free: 0.09905021},
+							{Asset: "USDT", Free: 1.89109409},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "AccountSnapshot margin",
+			method:  "GET",
+			path:    "/sapi/v1/accountSnapshot",
+			fixture: "account_snapshot_margin.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.AccountSnapshot(AccountSnapshotRequest{Type: SnapshotMargin, Timestamp: now})
+			},
+			want: &AccountSnapshot{
+				Snapshots: []*DailySnapshot{
+					{
+						Type:                "margin",
+						UpdateTime:          snapshotTime,
+						TotalAssetOfBTC:     0.00274803,
+						MarginLevel:         2748.02909813,
+						TotalLiabilityOfBTC: 0.000001,
+						TotalNetAssetOfBTC:  0.0027475,
+						UserAssets: []*MarginSnapshotAsset{
+							{Asset: "XRP", Free: 1, NetAsset: 1},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "AccountSnapshot futures",
+			method:  "GET",
+			path:    "/sapi/v1/accountSnapshot",
+			fixture: "account_snapshot_futures.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.AccountSnapshot(AccountSnapshotRequest{Type: SnapshotFutures, Timestamp: now})
+			},
+			want: &AccountSnapshot{
+				Snapshots: []*DailySnapshot{
+					{
+						Type:       "futures",
+						UpdateTime: snapshotTime,
+						FuturesAssets: []*FuturesSnapshotAsset{
+							{Asset: "USDT", MarginBalance: 118.99782335, WalletBalance: 120.23811389},
+						},
+						Positions: []*FuturesSnapshotPosition{
+							{
+								Symbol:           "BTCUSDT",
+								EntryPrice:       7130.41,
+								MarkPrice:        7257.66239673,
+								PositionAmt:      0.01,
+								UnRealizedProfit: 1.24029054,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "UniversalTransfer",
+			method:  "POST",
+			path:    "/sapi/v1/asset/transfer",
+			fixture: "asset_transfer.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.UniversalTransfer(UniversalTransferRequest{
+					Type:      TransferMainUMFuture,
+					Asset:     "USDT",
+					Amount:    10,
+					Timestamp: now,
+				})
+			},
+			want: &TransferResult{TranID: 13526853623},
+		},
+		{
+			name:    "FundingAssets",
+			method:  "POST",
+			path:    "/sapi/v1/asset/get-funding-asset",
+			fixture: "funding_assets.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.FundingAssets(FundingAssetsRequest{NeedBTCValuation: true, Timestamp: now})
+			},
+			want: []*FundingAsset{
+				{Asset: "USDT", Free: 1, BTCValuation: 0.00000091},
+			},
+		},
+		{
+			name:    "UserAssets",
+			method:  "POST",
+			path:    "/sapi/v3/asset/getUserAsset",
+			fixture: "user_assets.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.UserAssets(FundingAssetsRequest{NeedBTCValuation: true, Timestamp: now})
+			},
+			want: []*FundingAsset{
+				{Asset: "AVAX", Free: 1},
+				{Asset: "BNB", Free: 2.81, Locked: 0.5, BTCValuation: 0.02515682},
+			},
+		},
+		{
+			name:    "DustEstimate",
+			method:  "POST",
+			path:    "/sapi/v1/asset/dust-btc",
+			fixture: "dust_btc.json",
+			call: func(as *apiService) (interface{}, error) {
+				return as.DustEstimate()
+			},
+			want: &DustEstimate{
+				Details: []*DustAsset{
+					{
+						Asset:            "ADA",
+						AssetFullName:    "ADA",
+						AmountFree:       6.21,
+						ToBTC:            0.00016848,
+						ToBNB:            0.01777302,
+						ToBNBOffExchange: 0.01741756,
+						Exchange:         0.00035546,
+					},
+				},
+				TotalTransferBTC:   0.00016848,
+				TotalTransferBNB:   0.01777302,
+				DribbletPercentage: 0.02,
+			},
+		},
+	})
+}
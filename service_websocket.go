@@ -1,483 +1,945 @@
 package binance
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"net"
+	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-kit/kit/log/level"
 	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
 )
 
 func (as *apiService) DepthWebsocket(dwr DepthWebsocketRequest) (chan *DepthEvent, chan struct{}, error) {
-	url := fmt.Sprintf("wss://stream.binance.com:9443/ws/%s@depth", strings.ToLower(dwr.Symbol))
-	c, _, err := websocket.DefaultDialer.Dial(url, nil)
+	as = as.streamService()
+	url := fmt.Sprintf("wss://stream.binance.com:9443/ws/%s@depth", streamSymbol(dwr.Symbol))
+
+	dech := make(chan *DepthEvent)
+	// updates wait until snapshot is sent
+	snapshotSent := make(chan struct{})
+	if !dwr.WithSnapshot {
+		close(snapshotSent)
+	}
+	done, err := as.wsServe(url, func(message []byte) error {
+		rawDepth := struct {
+			Type          string          `json:"e"`
+			Time          int64           `json:"E"`
+			Symbol        string          `json:"s"`
+			FirstUpdateID lenientInt      `json:"U"`
+			UpdateID      lenientInt      `json:"u"`
+			PrevUpdateID  lenientInt      `json:"pu"`
+			BidDepthDelta [][]interface{} `json:"b"`
+			AskDepthDelta [][]interface{} `json:"a"`
+		}{}
+		if err := json.Unmarshal(message, &rawDepth); err != nil {
+			return errors.Wrap(err, "rawDepth unmarshal failed")
+		}
+		de := &DepthEvent{
+			WSEvent: WSEvent{
+				Type:   rawDepth.Type,
+				Time:   timeFromUnixMillis(rawDepth.Time),
+				Symbol: rawDepth.Symbol,
+				Raw:    as.rawEvent(message),
+			},
+			FirstUpdateID: int(rawDepth.FirstUpdateID),
+			UpdateID:      int(rawDepth.UpdateID),
+			PrevUpdateID:  int(rawDepth.PrevUpdateID),
+		}
+		for _, b := range rawDepth.BidDepthDelta {
+			p, err := floatFromString(b[0])
+			if err != nil {
+				return errors.Wrap(err, "cannot parse DepthEvent.Bids.Price")
+			}
+			q, err := floatFromString(b[1])
+			if err != nil {
+				return errors.Wrap(err, "cannot parse DepthEvent.Bids.Quantity")
+			}
+			de.Bids = append(de.Bids, &Order{
+				Price:       p,
+				Quantity:    q,
+				RawPrice:    b[0].(string),
+				RawQuantity: b[1].(string),
+			})
+		}
+		for _, a := range rawDepth.AskDepthDelta {
+			p, err := floatFromString(a[0])
+			if err != nil {
+				return errors.Wrap(err, "cannot parse DepthEvent.Asks.Price")
+			}
+			q, err := floatFromString(a[1])
+			if err != nil {
+				return errors.Wrap(err, "cannot parse DepthEvent.Asks.Quantity")
+			}
+			de.Asks = append(de.Asks, &Order{
+				Price:       p,
+				Quantity:    q,
+				RawPrice:    a[0].(string),
+				RawQuantity: a[1].(string),
+			})
+		}
+		select {
+		case <-snapshotSent:
+		case <-as.Ctx.Done():
+			return nil
+		}
+		select {
+		case dech <- de:
+		case <-as.Ctx.Done():
+		}
+		return nil
+	})
 	if err != nil {
-		log.Fatal("dial:", err)
+		return nil, nil, err
 	}
+	if dwr.WithSnapshot {
+		ob, err := as.OrderBook(OrderBookRequest{
+			Symbol: dwr.Symbol,
+			Limit:  dwr.SnapshotLimit,
+		})
+		if err != nil {
+			as.stopStream()
+			return nil, nil, err
+		}
+		// order book response carries no time, so the event is stamped when
+		// the snapshot was fetched
+		fetched := time.Now().UTC()
+		go func() {
+			defer close(snapshotSent)
+			select {
+			case dech <- &DepthEvent{
+				WSEvent: WSEvent{
+					Type:   DepthSnapshotEventType,
+					Time:   fetched,
+					Symbol: NormalizeSymbol(dwr.Symbol),
+				},
+				UpdateID:   ob.LastUpdateID,
+				IsSnapshot: true,
+				OrderBook:  *ob,
+			}:
+			case <-as.Ctx.Done():
+			}
+		}()
+	}
+	return dech, done, nil
+}
 
-	done := make(chan struct{})
-	dech := make(chan *DepthEvent)
+func (as *apiService) KlineWebsocket(kwr KlineWebsocketRequest) (chan *KlineEvent, chan struct{}, error) {
+	as = as.streamService()
+	intervals := kwr.Intervals
+	if kwr.Interval != "" {
+		intervals = append([]Interval{kwr.Interval}, intervals...)
+	}
+	url, combined := klineStreamURL(kwr.Symbol, intervals)
+
+	kech := make(chan *KlineEvent)
+	done, err := as.wsServe(url, func(message []byte) error {
+		if combined {
+			data, err := combinedStreamData(message)
+			if err != nil {
+				return err
+			}
+			message = data
+		}
+		ke, err := parseKlineEvent(message)
+		if err != nil {
+			return err
+		}
+		ke.Raw = as.rawEvent(message)
+		select {
+		case kech <- ke:
+		case <-as.Ctx.Done():
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return kech, done, nil
+}
 
+// klineStreamURL returns URL of kline stream of symbol and intervals and
+// whether it's combined stream, which is used for more than one interval.
+func klineStreamURL(symbol string, intervals []Interval) (string, bool) {
+	streams := make([]string, 0, len(intervals))
+	for _, i := range intervals {
+		streams = append(streams, fmt.Sprintf("%s@kline_%s", streamSymbol(symbol), string(i)))
+	}
+	if len(streams) > 1 {
+		return fmt.Sprintf("wss://stream.binance.com:9443/stream?streams=%s", strings.Join(streams, "/")), true
+	}
+	return fmt.Sprintf("wss://stream.binance.com:9443/ws/%s", strings.Join(streams, "/")), false
+}
+
+func (as *apiService) KlinesWithLive(symbol string, interval Interval, lookback int) (chan *KlineEvent, chan struct{}, error) {
+	klines, err := as.Klines(KlinesRequest{
+		Symbol:   symbol,
+		Interval: interval,
+		Limit:    lookback,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	live, done, err := as.KlineWebsocket(KlineWebsocketRequest{
+		Symbol:   symbol,
+		Interval: interval,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	url, _ := klineStreamURL(symbol, []Interval{interval})
+
+	kech := make(chan *KlineEvent)
 	go func() {
-		defer c.Close()
-		defer close(done)
-		for {
-			select {
-			case <-as.Ctx.Done():
-				level.Info(as.Logger).Log("closing reader")
-				return
-			default:
-				_, message, err := c.ReadMessage()
-				if err != nil {
-					level.Error(as.Logger).Log("wsRead", err)
-					return
-				}
-				rawDepth := struct {
-					Type          string          `json:"e"`
-					Time          float64         `json:"E"`
-					Symbol        string          `json:"s"`
-					UpdateID      int             `json:"u"`
-					BidDepthDelta [][]interface{} `json:"b"`
-					AskDepthDelta [][]interface{} `json:"a"`
-				}{}
-				if err := json.Unmarshal(message, &rawDepth); err != nil {
-					level.Error(as.Logger).Log("wsUnmarshal", err, "body", string(message))
-					return
-				}
-				t, err := timeFromUnixTimestampFloat(rawDepth.Time)
-				if err != nil {
-					level.Error(as.Logger).Log("wsUnmarshal", err, "body", string(message))
-					return
-				}
-				de := &DepthEvent{
+		var last time.Time
+		send := func(klines []*Kline) bool {
+			now := time.Now()
+			for _, k := range klines {
+				if k.OpenTime.Before(last) {
+					continue
+				}
+				last = k.OpenTime
+				ke := &KlineEvent{
 					WSEvent: WSEvent{
-						Type:   rawDepth.Type,
-						Time:   t,
-						Symbol: rawDepth.Symbol,
+						Type:   "kline",
+						Time:   now.UTC(),
+						Symbol: NormalizeSymbol(symbol),
 					},
-					UpdateID: rawDepth.UpdateID,
+					Interval: interval,
+					Final:    k.CloseTime.Before(now),
+					Kline:    *k,
 				}
-				for _, b := range rawDepth.BidDepthDelta {
-					p, err := floatFromString(b[0])
-					if err != nil {
-						level.Error(as.Logger).Log("wsUnmarshal", err, "body", string(message))
-						return
-					}
-					q, err := floatFromString(b[1])
-					if err != nil {
-						level.Error(as.Logger).Log("wsUnmarshal", err, "body", string(message))
-						return
-					}
-					de.Bids = append(de.Bids, &Order{
-						Price:    p,
-						Quantity: q,
-					})
+				select {
+				case kech <- ke:
+				case <-done:
+					return false
 				}
-				for _, b := range rawDepth.AskDepthDelta {
-					p, err := floatFromString(b[0])
+			}
+			return true
+		}
+		if !send(klines) {
+			return
+		}
+		backfilled := false
+		for {
+			select {
+			case ke := <-live:
+				if ke.OpenTime.Before(last) {
+					continue
+				}
+				if ke.OpenTime.After(last) && !backfilled && !last.IsZero() {
+					// fill klines closed before stream was connected
+					backfilled = true
+					gap, err := as.Klines(KlinesRequest{
+						Symbol:    symbol,
+						Interval:  interval,
+						StartTime: unixMillis(last),
+						EndTime:   unixMillis(ke.OpenTime) - 1,
+					})
 					if err != nil {
-						level.Error(as.Logger).Log("wsUnmarshal", err, "body", string(message))
+						err = errors.Wrap(err, "klines backfill failed")
+						as.reportStreamError(StreamErrorBackfill, url, done, err)
+						as.CloseStream(done)
+						<-done
 						return
 					}
-					q, err := floatFromString(b[1])
-					if err != nil {
-						level.Error(as.Logger).Log("wsUnmarshal", err, "body", string(message))
+					if !send(gap) {
 						return
 					}
-					de.Asks = append(de.Asks, &Order{
-						Price:    p,
-						Quantity: q,
-					})
 				}
-				dech <- de
+				backfilled = true
+				last = ke.OpenTime
+				select {
+				case kech <- ke:
+				case <-done:
+					return
+				}
+			case <-done:
+				return
 			}
 		}
 	}()
-
-	go as.exitHandler(c, done)
-	return dech, done, nil
+	return kech, done, nil
 }
 
-func (as *apiService) KlineWebsocket(kwr KlineWebsocketRequest) (chan *KlineEvent, chan struct{}, error) {
-	url := fmt.Sprintf("wss://stream.binance.com:9443/ws/%s@kline_%s", strings.ToLower(kwr.Symbol), string(kwr.Interval))
-	c, _, err := websocket.DefaultDialer.Dial(url, nil)
+func parseKlineEvent(message []byte) (*KlineEvent, error) {
+	rawKline := struct {
+		Type     string `json:"e"`
+		Time     int64  `json:"E"`
+		Symbol   string `json:"s"`
+		OpenTime int64  `json:"t"`
+		Kline    struct {
+			Interval                 string `json:"i"`
+			FirstTradeID             int64  `json:"f"`
+			LastTradeID              int64  `json:"L"`
+			Final                    bool   `json:"x"`
+			OpenTime                 int64  `json:"t"`
+			CloseTime                int64  `json:"T"`
+			Open                     string `json:"o"`
+			High                     string `json:"h"`
+			Low                      string `json:"l"`
+			Close                    string `json:"c"`
+			Volume                   string `json:"v"`
+			NumberOfTrades           int    `json:"n"`
+			QuoteAssetVolume         string `json:"q"`
+			TakerBuyBaseAssetVolume  string `json:"V"`
+			TakerBuyQuoteAssetVolume string `json:"Q"`
+		} `json:"k"`
+	}{}
+	if err := json.Unmarshal(message, &rawKline); err != nil {
+		return nil, errors.Wrap(err, "rawKline unmarshal failed")
+	}
+	open, err := floatFromString(rawKline.Kline.Open)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse KlineEvent.Open")
+	}
+	cls, err := floatFromString(rawKline.Kline.Close)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse KlineEvent.Close")
+	}
+	high, err := floatFromString(rawKline.Kline.High)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse KlineEvent.High")
+	}
+	low, err := floatFromString(rawKline.Kline.Low)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse KlineEvent.Low")
+	}
+	vol, err := floatFromString(rawKline.Kline.Volume)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse KlineEvent.Volume")
+	}
+	qav, err := floatFromString(rawKline.Kline.QuoteAssetVolume)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse KlineEvent.QuoteAssetVolume")
+	}
+	tbbav, err := floatFromString(rawKline.Kline.TakerBuyBaseAssetVolume)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse KlineEvent.TakerBuyBaseAssetVolume")
+	}
+	tbqav, err := floatFromString(rawKline.Kline.TakerBuyQuoteAssetVolume)
 	if err != nil {
-		log.Fatal("dial:", err)
+		return nil, errors.Wrap(err, "cannot parse KlineEvent.TakerBuyQuoteAssetVolume")
 	}
 
-	done := make(chan struct{})
-	kech := make(chan *KlineEvent)
+	return &KlineEvent{
+		WSEvent: WSEvent{
+			Type:   rawKline.Type,
+			Time:   timeFromUnixMillis(rawKline.Time),
+			Symbol: rawKline.Symbol,
+		},
+		Interval:     Interval(rawKline.Kline.Interval),
+		FirstTradeID: rawKline.Kline.FirstTradeID,
+		LastTradeID:  rawKline.Kline.LastTradeID,
+		Final:        rawKline.Kline.Final,
+		Kline: Kline{
+			OpenTime:                 timeFromUnixMillis(rawKline.Kline.OpenTime),
+			CloseTime:                timeFromUnixMillis(rawKline.Kline.CloseTime),
+			Open:                     open,
+			Close:                    cls,
+			High:                     high,
+			Low:                      low,
+			Volume:                   vol,
+			NumberOfTrades:           rawKline.Kline.NumberOfTrades,
+			QuoteAssetVolume:         qav,
+			TakerBuyBaseAssetVolume:  tbbav,
+			TakerBuyQuoteAssetVolume: tbqav,
+
+			RawOpen:                     rawKline.Kline.Open,
+			RawHigh:                     rawKline.Kline.High,
+			RawLow:                      rawKline.Kline.Low,
+			RawClose:                    rawKline.Kline.Close,
+			RawVolume:                   rawKline.Kline.Volume,
+			RawQuoteAssetVolume:         rawKline.Kline.QuoteAssetVolume,
+			RawTakerBuyBaseAssetVolume:  rawKline.Kline.TakerBuyBaseAssetVolume,
+			RawTakerBuyQuoteAssetVolume: rawKline.Kline.TakerBuyQuoteAssetVolume,
+		},
+	}, nil
+}
 
-	go func() {
-		defer c.Close()
-		defer close(done)
-		for {
-			select {
-			case <-as.Ctx.Done():
-				level.Info(as.Logger).Log("closing reader")
-				return
-			default:
-				_, message, err := c.ReadMessage()
-				if err != nil {
-					level.Error(as.Logger).Log("wsRead", err)
-					return
-				}
-				rawKline := struct {
-					Type     string  `json:"e"`
-					Time     float64 `json:"E"`
-					Symbol   string  `json:"S"`
-					OpenTime float64 `json:"t"`
-					Kline    struct {
-						Interval                 string  `json:"i"`
-						FirstTradeID             int64   `json:"f"`
-						LastTradeID              int64   `json:"L"`
-						Final                    bool    `json:"x"`
-						OpenTime                 float64 `json:"t"`
-						CloseTime                float64 `json:"T"`
-						Open                     string  `json:"o"`
-						High                     string  `json:"h"`
-						Low                      string  `json:"l"`
-						Close                    string  `json:"c"`
-						Volume                   string  `json:"v"`
-						NumberOfTrades           int     `json:"n"`
-						QuoteAssetVolume         string  `json:"q"`
-						TakerBuyBaseAssetVolume  string  `json:"V"`
-						TakerBuyQuoteAssetVolume string  `json:"Q"`
-					} `json:"k"`
-				}{}
-				if err := json.Unmarshal(message, &rawKline); err != nil {
-					level.Error(as.Logger).Log("wsUnmarshal", err, "body", string(message))
-					return
-				}
-				t, err := timeFromUnixTimestampFloat(rawKline.Time)
-				if err != nil {
-					level.Error(as.Logger).Log("wsUnmarshal", err, "body", rawKline.Time)
-					return
-				}
-				ot, err := timeFromUnixTimestampFloat(rawKline.Kline.OpenTime)
-				if err != nil {
-					level.Error(as.Logger).Log("wsUnmarshal", err, "body", rawKline.Kline.OpenTime)
-					return
-				}
-				ct, err := timeFromUnixTimestampFloat(rawKline.Kline.CloseTime)
-				if err != nil {
-					level.Error(as.Logger).Log("wsUnmarshal", err, "body", rawKline.Kline.CloseTime)
-					return
-				}
-				open, err := floatFromString(rawKline.Kline.Open)
-				if err != nil {
-					level.Error(as.Logger).Log("wsUnmarshal", err, "body", rawKline.Kline.Open)
-					return
-				}
-				cls, err := floatFromString(rawKline.Kline.Close)
-				if err != nil {
-					level.Error(as.Logger).Log("wsUnmarshal", err, "body", rawKline.Kline.Close)
-					return
-				}
-				high, err := floatFromString(rawKline.Kline.High)
-				if err != nil {
-					level.Error(as.Logger).Log("wsUnmarshal", err, "body", rawKline.Kline.High)
-					return
-				}
-				low, err := floatFromString(rawKline.Kline.Low)
-				if err != nil {
-					level.Error(as.Logger).Log("wsUnmarshal", err, "body", rawKline.Kline.Low)
-					return
-				}
-				vol, err := floatFromString(rawKline.Kline.Volume)
-				if err != nil {
-					level.Error(as.Logger).Log("wsUnmarshal", err, "body", rawKline.Kline.Volume)
-					return
-				}
-				qav, err := floatFromString(rawKline.Kline.QuoteAssetVolume)
-				if err != nil {
-					level.Error(as.Logger).Log("wsUnmarshal", err, "body", (rawKline.Kline.QuoteAssetVolume))
-					return
-				}
-				tbbav, err := floatFromString(rawKline.Kline.TakerBuyBaseAssetVolume)
-				if err != nil {
-					level.Error(as.Logger).Log("wsUnmarshal", err, "body", rawKline.Kline.TakerBuyBaseAssetVolume)
-					return
-				}
-				tbqav, err := floatFromString(rawKline.Kline.TakerBuyQuoteAssetVolume)
-				if err != nil {
-					level.Error(as.Logger).Log("wsUnmarshal", err, "body", rawKline.Kline.TakerBuyQuoteAssetVolume)
-					return
-				}
+// combinedStreamData unwraps payload of message received from combined
+// stream, which comes as {"stream":"<streamName>","data":<rawPayload>}.
+func combinedStreamData(message []byte) ([]byte, error) {
+	rawStream := struct {
+		Stream string          `json:"stream"`
+		Data   json.RawMessage `json:"data"`
+	}{}
+	if err := json.Unmarshal(message, &rawStream); err != nil {
+		return nil, errors.Wrap(err, "rawStream unmarshal failed")
+	}
+	return rawStream.Data, nil
+}
 
-				ke := &KlineEvent{
-					WSEvent: WSEvent{
-						Type:   rawKline.Type,
-						Time:   t,
-						Symbol: rawKline.Symbol,
-					},
-					Interval:     Interval(rawKline.Kline.Interval),
-					FirstTradeID: rawKline.Kline.FirstTradeID,
-					LastTradeID:  rawKline.Kline.LastTradeID,
-					Final:        rawKline.Kline.Final,
-					Kline: Kline{
-						OpenTime:                 ot,
-						CloseTime:                ct,
-						Open:                     open,
-						Close:                    cls,
-						High:                     high,
-						Low:                      low,
-						Volume:                   vol,
-						NumberOfTrades:           rawKline.Kline.NumberOfTrades,
-						QuoteAssetVolume:         qav,
-						TakerBuyBaseAssetVolume:  tbbav,
-						TakerBuyQuoteAssetVolume: tbqav,
-					},
-				}
-				kech <- ke
-			}
-		}
-	}()
+func (as *apiService) AggTradeWebsocket(twr AggTradeWebsocketRequest) (chan *AggTradeEvent, chan struct{}, error) {
+	as = as.streamService()
+	url := fmt.Sprintf("wss://stream.binance.com:9443/ws/%s@aggTrade", streamSymbol(twr.Symbol))
 
-	go as.exitHandler(c, done)
-	return kech, done, nil
+	aggtech := make(chan *AggTradeEvent)
+	done, err := as.wsServe(url, func(message []byte) error {
+		rawAggTrade := struct {
+			Type         string `json:"e"`
+			Time         int64  `json:"E"`
+			Symbol       string `json:"s"`
+			TradeID      int    `json:"a"`
+			Price        string `json:"p"`
+			Quantity     string `json:"q"`
+			FirstTradeID int    `json:"f"`
+			LastTradeID  int    `json:"l"`
+			Timestamp    int64  `json:"T"`
+			IsMaker      bool   `json:"m"`
+			BestMatch    bool   `json:"M"`
+		}{}
+		if err := json.Unmarshal(message, &rawAggTrade); err != nil {
+			return errors.Wrap(err, "rawAggTrade unmarshal failed")
+		}
+		price, err := floatFromString(rawAggTrade.Price)
+		if err != nil {
+			return errors.Wrap(err, "cannot parse AggTradeEvent.Price")
+		}
+		qty, err := floatFromString(rawAggTrade.Quantity)
+		if err != nil {
+			return errors.Wrap(err, "cannot parse AggTradeEvent.Quantity")
+		}
+		ae := &AggTradeEvent{
+			WSEvent: WSEvent{
+				Type:   rawAggTrade.Type,
+				Time:   timeFromUnixMillis(rawAggTrade.Time),
+				Symbol: rawAggTrade.Symbol,
+				Raw:    as.rawEvent(message),
+			},
+			AggTrade: AggTrade{
+				ID:             rawAggTrade.TradeID,
+				Price:          price,
+				Quantity:       qty,
+				FirstTradeID:   rawAggTrade.FirstTradeID,
+				LastTradeID:    rawAggTrade.LastTradeID,
+				Timestamp:      timeFromUnixMillis(rawAggTrade.Timestamp),
+				BuyerMaker:     rawAggTrade.IsMaker,
+				BestPriceMatch: rawAggTrade.BestMatch,
+				RawPrice:       rawAggTrade.Price,
+				RawQuantity:    rawAggTrade.Quantity,
+			},
+		}
+		select {
+		case aggtech <- ae:
+		case <-as.Ctx.Done():
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return aggtech, done, nil
 }
 
-func (as *apiService) AggTradeWebsocket(twr AggTradeWebsocketRequest) (chan *AggTradeEvent, chan struct{}, error) {
-	url := fmt.Sprintf("wss://stream.binance.com:9443/ws/%s@aggTrade", strings.ToLower(twr.Symbol))
-	c, _, err := websocket.DefaultDialer.Dial(url, nil)
+func (as *apiService) TradeWebsocket(twr TradeWebsocketRequest) (chan *TradeEvent, chan struct{}, error) {
+	as = as.streamService()
+	url := fmt.Sprintf("wss://stream.binance.com:9443/ws/%s@trade", streamSymbol(twr.Symbol))
+
+	tech := make(chan *TradeEvent)
+	done, err := as.wsServe(url, func(message []byte) error {
+		var rawTrade TradeEventResponse
+		if err := json.Unmarshal(message, &rawTrade); err != nil {
+			return errors.Wrap(err, "rawTrade unmarshal failed")
+		}
+
+		te := &TradeEvent{
+			WSEvent: WSEvent{
+				Type:   rawTrade.Type,
+				Time:   timeFromUnixMillis(rawTrade.EventTime),
+				Symbol: rawTrade.Symbol,
+				Raw:    as.rawEvent(message),
+			},
+			Trade: Trade{
+				ID:             rawTrade.TradeID,
+				Price:          rawTrade.Price,
+				Quantity:       rawTrade.Quantity,
+				BuyerId:        rawTrade.BuyerId,
+				SellerId:       rawTrade.SellerId,
+				TradeTime:      timeFromUnixMillis(rawTrade.TradeTime),
+				BuyerMaker:     rawTrade.IsMarketMaker,
+				BestPriceMatch: rawTrade.BestMatch,
+			},
+		}
+		select {
+		case tech <- te:
+		case <-as.Ctx.Done():
+		}
+		return nil
+	})
 	if err != nil {
-		log.Fatal("dial:", err)
+		return nil, nil, err
 	}
+	return tech, done, nil
+}
 
-	done := make(chan struct{})
-	aggtech := make(chan *AggTradeEvent)
+func (as *apiService) UserDataWebsocket(urwr UserDataWebsocketRequest) (chan *AccountEvent, chan struct{}, error) {
+	as = as.streamService()
+	url := fmt.Sprintf("wss://stream.binance.com:9443/ws/%s", urwr.ListenKey)
 
-	go func() {
-		defer c.Close()
-		defer close(done)
-		for {
+	aech := make(chan *AccountEvent)
+	done, err := as.wsServe(url, func(message []byte) error {
+		rawType := struct {
+			Type string `json:"e"`
+			Time uint64 `json:"E"`
+		}{}
+		if err := json.Unmarshal(message, &rawType); err != nil {
+			return errors.Wrap(err, "rawType unmarshal failed")
+		}
+		switch rawType.Type {
+		case "outboundAccountInfo":
+			var rawAccount OutboundAccountInfoEvent
+			if err := json.Unmarshal(message, &rawAccount); err != nil {
+				return errors.Wrap(err, "rawAccount unmarshal failed")
+			}
+
+			ae := &AccountEvent{
+				WSEvent: WSEvent{
+					Type: rawAccount.Type,
+					Time: timeFromUnixMillis(rawAccount.EventTime),
+					Raw:  as.rawEvent(message),
+				},
+				Account: Account{
+					MakerCommision:  rawAccount.MakerCommision,
+					TakerCommision:  rawAccount.TakerCommision,
+					BuyerCommision:  rawAccount.BuyerCommision,
+					SellerCommision: rawAccount.SellerCommision,
+					CanTrade:        rawAccount.CanTrade,
+					CanWithdraw:     rawAccount.CanWithdraw,
+					CanDeposit:      rawAccount.CanDeposit,
+					Balances:        rawAccount.Balances,
+				},
+			}
 			select {
+			case aech <- ae:
 			case <-as.Ctx.Done():
-				level.Info(as.Logger).Log("closing reader")
-				return
-			default:
-				_, message, err := c.ReadMessage()
-				if err != nil {
-					level.Error(as.Logger).Log("wsRead", err)
-					return
-				}
-				rawAggTrade := struct {
-					Type         string  `json:"e"`
-					Time         float64 `json:"E"`
-					Symbol       string  `json:"s"`
-					TradeID      int     `json:"a"`
-					Price        string  `json:"p"`
-					Quantity     string  `json:"q"`
-					FirstTradeID int     `json:"f"`
-					LastTradeID  int     `json:"l"`
-					Timestamp    float64 `json:"T"`
-					IsMaker      bool    `json:"m"`
-				}{}
-				if err := json.Unmarshal(message, &rawAggTrade); err != nil {
-					level.Error(as.Logger).Log("wsUnmarshal", err, "body", string(message))
-					return
-				}
-				t, err := timeFromUnixTimestampFloat(rawAggTrade.Time)
-				if err != nil {
-					level.Error(as.Logger).Log("wsUnmarshal", err, "body", rawAggTrade.Time)
-					return
-				}
+			}
 
-				price, err := floatFromString(rawAggTrade.Price)
-				if err != nil {
-					level.Error(as.Logger).Log("wsUnmarshal", err, "body", rawAggTrade.Price)
-					return
-				}
-				qty, err := floatFromString(rawAggTrade.Quantity)
-				if err != nil {
-					level.Error(as.Logger).Log("wsUnmarshal", err, "body", rawAggTrade.Quantity)
-					return
-				}
-				ts, err := timeFromUnixTimestampFloat(rawAggTrade.Timestamp)
-				if err != nil {
-					level.Error(as.Logger).Log("wsUnmarshal", err, "body", rawAggTrade.Timestamp)
-					return
-				}
+		case "outboundAccountPosition":
+			rawPosition := struct {
+				Type           string     `json:"e"`
+				Time           int64      `json:"E"`
+				LastUpdateTime int64      `json:"u"`
+				Balances       []*Balance `json:"B"`
+			}{}
+			if err := json.Unmarshal(message, &rawPosition); err != nil {
+				return errors.Wrap(err, "rawPosition unmarshal failed")
+			}
 
-				ae := &AggTradeEvent{
-					WSEvent: WSEvent{
-						Type:   rawAggTrade.Type,
-						Time:   t,
-						Symbol: rawAggTrade.Symbol,
-					},
-					AggTrade: AggTrade{
-						ID:           rawAggTrade.TradeID,
-						Price:        price,
-						Quantity:     qty,
-						FirstTradeID: rawAggTrade.FirstTradeID,
-						LastTradeID:  rawAggTrade.LastTradeID,
-						Timestamp:    ts,
-						BuyerMaker:   rawAggTrade.IsMaker,
-					},
-				}
-				aggtech <- ae
+			ae := &AccountEvent{
+				WSEvent: WSEvent{
+					Type: rawPosition.Type,
+					Time: timeFromUnixMillis(rawPosition.Time),
+					Raw:  as.rawEvent(message),
+				},
+				AccountPosition: &AccountPosition{
+					LastUpdateTime: timeFromUnixMillis(rawPosition.LastUpdateTime),
+					Balances:       rawPosition.Balances,
+				},
+			}
+			select {
+			case aech <- ae:
+			case <-as.Ctx.Done():
 			}
-		}
-	}()
 
-	go as.exitHandler(c, done)
-	return aggtech, done, nil
-}
+		case "listStatus":
+			rawListStatus := struct {
+				Type              string `json:"e"`
+				Time              int64  `json:"E"`
+				Symbol            string `json:"s"`
+				OrderListID       int64  `json:"g"`
+				ContingencyType   string `json:"c"`
+				ListStatusType    string `json:"l"`
+				ListOrderStatus   string `json:"L"`
+				ListRejectReason  string `json:"r"`
+				ListClientOrderID string `json:"C"`
+				TransactionTime   int64  `json:"T"`
+				Orders            []struct {
+					Symbol        string     `json:"s"`
+					OrderID       lenientInt `json:"i"`
+					ClientOrderID string     `json:"c"`
+				} `json:"O"`
+			}{}
+			if err := json.Unmarshal(message, &rawListStatus); err != nil {
+				return errors.Wrap(err, "rawListStatus unmarshal failed")
+			}
 
-func (as *apiService) TradeWebsocket(twr TradeWebsocketRequest) (chan *TradeEvent, chan struct{}, error) {
-	url := fmt.Sprintf("wss://stream.binance.com:9443/ws/%s@trade", strings.ToLower(twr.Symbol))
-	c, _, err := websocket.DefaultDialer.Dial(url, nil)
-	if err != nil {
-		log.Fatal("dial:", err)
-	}
+			ls := &ListStatus{
+				Symbol:            rawListStatus.Symbol,
+				OrderListID:       rawListStatus.OrderListID,
+				ContingencyType:   rawListStatus.ContingencyType,
+				ListStatusType:    rawListStatus.ListStatusType,
+				ListOrderStatus:   rawListStatus.ListOrderStatus,
+				ListRejectReason:  rawListStatus.ListRejectReason,
+				ListClientOrderID: rawListStatus.ListClientOrderID,
+				TransactionTime:   timeFromUnixMillis(rawListStatus.TransactionTime),
+			}
+			for _, o := range rawListStatus.Orders {
+				ls.Orders = append(ls.Orders, &ListStatusOrder{
+					Symbol:        o.Symbol,
+					OrderID:       int64(o.OrderID),
+					ClientOrderID: o.ClientOrderID,
+				})
+			}
+			ae := &AccountEvent{
+				WSEvent: WSEvent{
+					Type:   rawListStatus.Type,
+					Time:   timeFromUnixMillis(rawListStatus.Time),
+					Symbol: rawListStatus.Symbol,
+					Raw:    as.rawEvent(message),
+				},
+				ListStatus: ls,
+			}
+			select {
+			case aech <- ae:
+			case <-as.Ctx.Done():
+			}
 
-	done := make(chan struct{})
-	aggtech := make(chan *TradeEvent)
+		case "balanceUpdate":
+			rawBalanceUpdate := struct {
+				Type      string  `json:"e"`
+				Time      int64   `json:"E"`
+				Asset     string  `json:"a"`
+				Delta     float64 `json:"d,string"`
+				ClearTime int64   `json:"T"`
+			}{}
+			if err := json.Unmarshal(message, &rawBalanceUpdate); err != nil {
+				return errors.Wrap(err, "rawBalanceUpdate unmarshal failed")
+			}
 
-	go func() {
-		defer c.Close()
-		defer close(done)
-		for {
+			ae := &AccountEvent{
+				WSEvent: WSEvent{
+					Type: rawBalanceUpdate.Type,
+					Time: timeFromUnixMillis(rawBalanceUpdate.Time),
+					Raw:  as.rawEvent(message),
+				},
+				BalanceUpdate: &BalanceUpdate{
+					Asset:     rawBalanceUpdate.Asset,
+					Delta:     rawBalanceUpdate.Delta,
+					ClearTime: timeFromUnixMillis(rawBalanceUpdate.ClearTime),
+				},
+			}
 			select {
+			case aech <- ae:
 			case <-as.Ctx.Done():
-				level.Info(as.Logger).Log("closing reader")
-				return
-			default:
-				_, message, err := c.ReadMessage()
-				if err != nil {
-					level.Error(as.Logger).Log("wsRead", err)
-					return
-				}
+			}
 
-				var rawTrade TradeEventResponse
-				if err := json.Unmarshal(message, &rawTrade); err != nil {
-					level.Error(as.Logger).Log("wsUnmarshal", err, "body", string(message))
-					return
-				}
+		case "executionReport":
+			var executionReport ExecutionReportEvent
+			if err := json.Unmarshal(message, &executionReport); err != nil {
+				return errors.Wrap(err, "executionReport unmarshal failed")
+			}
+			ae := &AccountEvent{
+				WSEvent: WSEvent{
+					Type:   executionReport.Type,
+					Time:   timeFromUnixMillis(executionReport.EventTime),
+					Symbol: executionReport.Symbol,
+					Raw:    as.rawEvent(message),
+				},
+				ExecutionReport: &executionReport,
+			}
+			select {
+			case aech <- ae:
+			case <-as.Ctx.Done():
+			}
 
-				aggtech <- &TradeEvent{
-					WSEvent: WSEvent{
-						Type:   rawTrade.Type,
-						Time:   time.Unix(0, rawTrade.EventTime*int64(time.Millisecond)),
-						Symbol: rawTrade.Symbol,
-					},
-					Trade: Trade{
-						ID:         rawTrade.TradeID,
-						Price:      rawTrade.Price,
-						Quantity:   rawTrade.Quantity,
-						BuyerId:    rawTrade.BuyerId,
-						SellerId:   rawTrade.SellerId,
-						TradeTime:  time.Unix(0, rawTrade.TradeTime*int64(time.Millisecond)),
-						BuyerMaker: rawTrade.IsMarketMaker,
-					},
-				}
+		case "listenKeyExpired":
+			rawExpired := struct {
+				Type      string `json:"e"`
+				Time      int64  `json:"E"`
+				ListenKey string `json:"listenKey"`
+			}{}
+			if err := json.Unmarshal(message, &rawExpired); err != nil {
+				return errors.Wrap(err, "rawExpired unmarshal failed")
+			}
+			level.Warn(as.Logger).Log("userDataWebsocket", "listen key expired")
+
+			ae := &AccountEvent{
+				WSEvent: WSEvent{
+					Type: rawExpired.Type,
+					Time: timeFromUnixMillis(rawExpired.Time),
+					Raw:  as.rawEvent(message),
+				},
+				ListenKeyExpired: &ListenKeyExpired{
+					ListenKey: rawExpired.ListenKey,
+				},
+			}
+			select {
+			case aech <- ae:
+			case <-as.Ctx.Done():
 			}
 		}
-	}()
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return aech, done, nil
+}
 
-	go as.exitHandler(c, done)
-	return aggtech, done, nil
+// wsHandshakeTimeout limits websocket handshake of default dialer.
+const wsHandshakeTimeout = 10 * time.Second
+
+// NewDialer returns websocket dialer used by default, which honors proxy
+// environment variables and limits handshake by wsHandshakeTimeout.
+func NewDialer() *websocket.Dialer {
+	return &websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: wsHandshakeTimeout,
+	}
 }
 
-func (as *apiService) UserDataWebsocket(urwr UserDataWebsocketRequest) (chan *AccountEvent, chan struct{}, error) {
-	url := fmt.Sprintf("wss://stream.binance.com:9443/ws/%s", urwr.ListenKey)
-	c, _, err := websocket.DefaultDialer.Dial(url, nil)
+// wsHandler handles single websocket message. Returned error stops the stream.
+type wsHandler func(message []byte) error
+
+// wsConn wraps websocket connection so that it's closed exactly once.
+type wsConn struct {
+	*websocket.Conn
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// Close closes underlying connection, subsequent calls are no-op.
+func (c *wsConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.closeErr = c.Conn.Close()
+	})
+	return c.closeErr
+}
+
+// wsServe dials url and passes every received message to handler until
+// service context is cancelled or an error occurs. Responses to control
+// requests, e.g. subscription acks, are skipped. Stream silent for longer
+// than stale stream timeout is reconnected.
+//
+// Connection is owned by reader goroutine which is the only one closing it;
+// returned done is closed after that, so closed done means the stream is
+// fully torn down.
+func (as *apiService) wsServe(url string, handler wsHandler) (chan struct{}, error) {
+	c, err := as.wsDial(url)
 	if err != nil {
-		log.Fatal("dial:", err)
+		as.stopStream()
+		return nil, err
+	}
+	redial := func() (*wsConn, error) {
+		return as.wsDial(url)
 	}
+	return as.wsServeConn(c, url, redial, func(message []byte) error {
+		if isControlFrame(message) {
+			level.Debug(as.Logger).Log("wsControlFrame", string(message))
+			return nil
+		}
+		return handler(message)
+	}), nil
+}
 
-	done := make(chan struct{})
-	aech := make(chan *AccountEvent)
+// isControlFrame reports whether message is response to control request,
+// e.g. {"result":null,"id":1}, rather than stream event.
+func isControlFrame(message []byte) bool {
+	if !bytes.Contains(message, []byte(`"id"`)) {
+		return false
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(message, &fields); err != nil {
+		return false
+	}
+	_, id := fields["id"]
+	_, result := fields["result"]
+	_, errField := fields["error"]
+	_, event := fields["e"]
+	return id && (result || errField) && !event
+}
+
+func (as *apiService) wsDial(url string) (*wsConn, error) {
+	conn, _, err := as.dialer.DialContext(as.Ctx, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "websocket dial failed")
+	}
+	return &wsConn{Conn: conn}, nil
+}
 
+// errStreamStale is returned by wsRead when no message was received within
+// stale stream timeout.
+var errStreamStale = errors.New("no message received within stale stream timeout")
+
+// wsServeConn reads messages of connection c until it fails or service
+// context is canceled, and returns channel closed after connection is closed.
+//
+// Connection silent for longer than stale stream timeout is replaced by one
+// returned by redial, streams without redial are never considered stale.
+func (as *apiService) wsServeConn(c *wsConn, url string, redial func() (*wsConn, error),
+	handler wsHandler) chan struct{} {
+	done := make(chan struct{})
+	as.streams.add(done, url, as.cancelStream)
 	go func() {
-		defer c.Close()
 		defer close(done)
+		defer as.streams.remove(done)
+		defer as.stopStream()
 		for {
-			select {
-			case <-as.Ctx.Done():
-				level.Info(as.Logger).Log("closing reader")
+			err := as.wsRead(c, url, redial != nil, done, handler)
+			if err != errStreamStale {
 				return
-			default:
-				_, message, err := c.ReadMessage()
-				if err != nil {
-					level.Error(as.Logger).Log("wsRead", err)
-					return
-				}
+			}
+			level.Warn(as.Logger).Log("wsStale", url, "reconnecting", true)
+			if c, err = redial(); err != nil {
+				level.Error(as.Logger).Log("wsDial", err)
+				as.reportStreamError(StreamErrorRead, url, done, err)
+				return
+			}
+			as.streams.addReconnects(done, 1)
+		}
+	}()
+	return done
+}
 
-				rawType := struct {
-					Type string `json:"e"`
-					Time uint64 `json:"E"`
-				}{}
+// wsRead passes messages of connection c to handler until the connection
+// fails, and closes it. Returned error is nil if service context was
+// cancelled, errStreamStale if the connection is stale and watchdog is set.
+// Other errors are reported as stream errors of done.
+func (as *apiService) wsRead(c *wsConn, url string, watchdog bool, done chan struct{},
+	handler wsHandler) (streamErr error) {
+	as.observer.WebsocketConnected(url)
+	connDone := make(chan struct{})
+	defer close(connDone)
+	defer func() { as.observer.WebsocketDisconnected(url, streamErr) }()
+	defer c.Close()
+	go as.exitHandler(c, connDone)
 
-				if err := json.Unmarshal(message, &rawType); err != nil {
-					level.Error(as.Logger).Log("wsUnmarshal", err, "body", string(message))
-					return
+	stale := time.Duration(0)
+	if watchdog {
+		stale = as.wsStaleTimeout
+	}
+	for {
+		select {
+		case <-as.Ctx.Done():
+			level.Info(as.Logger).Log("closing reader")
+			return nil
+		default:
+			if stale > 0 {
+				c.SetReadDeadline(time.Now().Add(stale))
+			}
+			_, message, err := c.ReadMessage()
+			if err != nil {
+				if as.Ctx.Err() != nil {
+					level.Info(as.Logger).Log("closing reader")
+					return nil
 				}
-				switch rawType.Type {
-				case "outboundAccountInfo":
-					var rawAccount OutboundAccountInfoEvent
-					if err := json.Unmarshal(message, &rawAccount); err != nil {
-						level.Error(as.Logger).Log("wsUnmarshal", err, "body", string(message))
-						return
-					}
-
-					aech <- &AccountEvent{
-						WSEvent: WSEvent{
-							Type: rawAccount.Type,
-							Time: time.Unix(0, rawAccount.EventTime*int64(time.Millisecond)),
-						},
-						Account: Account{
-							MakerCommision:  rawAccount.MakerCommision,
-							TakerCommision:  rawAccount.TakerCommision,
-							BuyerCommision:  rawAccount.BuyerCommision,
-							SellerCommision: rawAccount.SellerCommision,
-							CanTrade:        rawAccount.CanTrade,
-							CanWithdraw:     rawAccount.CanWithdraw,
-							CanDeposit:      rawAccount.CanDeposit,
-							Balances:        rawAccount.Balances,
-						},
-					}
-
-				case "executionReport":
-					var executionReport ExecutionReportEvent
-					if err := json.Unmarshal(message, &executionReport); err != nil {
-						level.Error(as.Logger).Log("wsUnmarshal", err, "body", string(message))
-						return
-					}
-					level.Info(as.Logger).Log("executionReport", executionReport)
+				if ne, ok := err.(net.Error); ok && ne.Timeout() && stale > 0 {
+					return errStreamStale
 				}
+				level.Error(as.Logger).Log("wsRead", err)
+				as.reportStreamError(StreamErrorRead, url, done, err)
+				return err
+			}
+			as.streams.received(done)
+			if !json.Valid(message) {
+				level.Warn(as.Logger).Log("wsSkipped", "not JSON", "body", string(message))
+				continue
+			}
+			if err := handler(message); err != nil {
+				level.Error(as.Logger).Log("wsUnmarshal", err, "body", string(message))
+				as.reportStreamError(StreamErrorParse, url, done, err)
+				return err
 			}
 		}
-	}()
+	}
+}
 
-	go as.exitHandler(c, done)
-	return aech, done, nil
+const streamErrorsBuffer = 16
+
+// rawEvent returns message to be kept by WSEvent.Raw, which is nil unless
+// raw events are enabled.
+func (as *apiService) rawEvent(message []byte) []byte {
+	if !as.rawEvents {
+		return nil
+	}
+	return message
+}
+
+func (as *apiService) StreamErrors() <-chan error {
+	return as.streamErrors
 }
 
-func (as *apiService) exitHandler(c *websocket.Conn, done chan struct{}) {
+func (as *apiService) Streams() []StreamStatus {
+	return as.streams.snapshot()
+}
+
+func (as *apiService) CloseStream(done <-chan struct{}) {
+	as.streams.close(done)
+}
+
+func (as *apiService) Shutdown(ctx context.Context) error {
+	for _, done := range as.streams.closeAll() {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// streamService returns copy of service with own context derived from
+// service context, so that stream started by the copy can be stopped alone
+// by CloseStream. Stream methods replace their receiver with it.
+func (as *apiService) streamService() *apiService {
+	c := *as
+	c.Ctx, c.cancelStream = context.WithCancel(as.Ctx)
+	return &c
+}
+
+// stopStream cancels context of stream started by service returned by
+// streamService.
+func (as *apiService) stopStream() {
+	if as.cancelStream != nil {
+		as.cancelStream()
+	}
+}
+
+// reportStreamError sends StreamError without blocking the reader.
+func (as *apiService) reportStreamError(kind StreamErrorKind, url string, done chan struct{}, err error) {
+	select {
+	case as.streamErrors <- &StreamError{
+		Kind:   kind,
+		Stream: url,
+		Done:   done,
+		Err:    err,
+	}:
+	default:
+		level.Warn(as.Logger).Log("streamErrors", "channel full, error dropped")
+	}
+}
+
+// exitHandler keeps connection alive with pings and, once context is
+// cancelled, asks reader to stop. It never closes the connection itself.
+func (as *apiService) exitHandler(c *wsConn, done chan struct{}) {
 	ticker := time.NewTicker(time.Second)
 	defer ticker.Stop()
-	defer c.Close()
 
 	for {
 		select {
 		case <-ticker.C:
-			err := c.WriteMessage(websocket.PingMessage, []byte{})
+			err := c.WriteControl(websocket.PingMessage, []byte{}, time.Now().Add(time.Second))
 			if err != nil {
 				level.Error(as.Logger).Log("wsWrite", err)
 				return
 			}
-			//			level.Info(as.Logger).Log(t)
+		case <-done:
+			return
 		case <-as.Ctx.Done():
-			select {
-			case <-done:
-			case <-time.After(time.Second):
-			}
 			level.Info(as.Logger).Log("closing connection")
+			err := c.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+			if err != nil {
+				level.Error(as.Logger).Log("wsWrite", err)
+			}
+			// unblock reader in case server doesn't confirm close
+			c.SetReadDeadline(time.Now().Add(time.Second))
 			return
 		}
 	}
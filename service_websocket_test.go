@@ -0,0 +1,486 @@
+package binance
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+)
+
+const testKlineMinute = int64(60000)
+
+// testKlineRow returns REST kline row opened at openTime.
+func testKlineRow(openTime int64) string {
+	return fmt.Sprintf(`[%d,"0.0010","0.0025","0.0015","0.0020","1000",%d,"1.0",100,"500","0.5","0"]`,
+		openTime, openTime+testKlineMinute-1)
+}
+
+// testKlineEvent returns kline stream event of kline opened at openTime.
+func testKlineEvent(openTime int64) string {
+	return fmt.Sprintf(`{"e":"kline","E":%d,"s":"BNBBTC","k":{"t":%d,"T":%d,"s":"BNBBTC","i":"1m",`+
+		`"f":100,"L":200,"o":"0.0010","c":"0.0020","h":"0.0025","l":"0.0015","v":"1000","n":100,`+
+		`"x":false,"q":"1.0","V":"500","Q":"0.5","B":"0"}}`,
+		openTime+1000, openTime, openTime+testKlineMinute-1)
+}
+
+func TestKlinesWithLiveBackfillFailure(t *testing.T) {
+	first := int64(1600000000000)
+	var backfills int32
+	as := newTestStreamService(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/klines":
+			if r.URL.Query().Get("startTime") != "" {
+				atomic.AddInt32(&backfills, 1)
+				w.WriteHeader(http.StatusInternalServerError)
+				fmt.Fprint(w, `{"code":-1001,"msg":"Internal error."}`)
+				return
+			}
+			fmt.Fprintf(w, "[%s,%s]", testKlineRow(first), testKlineRow(first+testKlineMinute))
+		case "/ws/bnbbtc@kline_1m":
+			c := upgradeTestWS(t, w, r)
+			if c == nil {
+				return
+			}
+			// kline two minutes after the last historical one, so one is missing
+			c.WriteMessage(websocket.TextMessage, []byte(testKlineEvent(first+3*testKlineMinute)))
+			holdTestWS(c)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}, WithRetryPolicy(RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond}))
+
+	kech, done, err := as.KlinesWithLive("BNBBTC", Minute, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var received []*KlineEvent
+	timeout := time.After(5 * time.Second)
+loop:
+	for {
+		select {
+		case ke := <-kech:
+			received = append(received, ke)
+		case <-done:
+			break loop
+		case <-timeout:
+			t.Fatal("stream not stopped after failed backfill")
+		}
+	}
+	if len(received) != 2 {
+		t.Errorf("received %d klines, want 2 historical ones only", len(received))
+	}
+	if n := atomic.LoadInt32(&backfills); n != 2 {
+		t.Errorf("backfill requested %d times, want 2 attempts of retry policy", n)
+	}
+	select {
+	case err := <-as.StreamErrors():
+		se, ok := err.(*StreamError)
+		if !ok || se.Kind != StreamErrorBackfill || se.Done != done {
+			t.Errorf("unexpected stream error %#v", err)
+		}
+		if want, _ := klineStreamURL("BNBBTC", []Interval{Minute}); se.Stream != want {
+			t.Errorf("stream error reported for %q, want %q", se.Stream, want)
+		}
+		if apiErr, ok := errors.Cause(se).(*Error); !ok || apiErr.Code != -1001 {
+			t.Errorf("stream error doesn't carry API error: %v", se.Err)
+		}
+	default:
+		t.Error("backfill failure not reported")
+	}
+}
+
+func TestKlinesWithLiveBackfill(t *testing.T) {
+	first := int64(1600000000000)
+	as := newTestStreamService(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/klines":
+			if r.URL.Query().Get("startTime") != "" {
+				fmt.Fprintf(w, "[%s,%s]", testKlineRow(first+testKlineMinute), testKlineRow(first+2*testKlineMinute))
+				return
+			}
+			fmt.Fprintf(w, "[%s,%s]", testKlineRow(first), testKlineRow(first+testKlineMinute))
+		case "/ws/bnbbtc@kline_1m":
+			c := upgradeTestWS(t, w, r)
+			if c == nil {
+				return
+			}
+			c.WriteMessage(websocket.TextMessage, []byte(testKlineEvent(first+3*testKlineMinute)))
+			holdTestWS(c)
+		}
+	})
+
+	kech, _, err := as.KlinesWithLive("BNBBTC", Minute, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the last historical kline is updated by backfill, which starts with it
+	for i, minute := range []int64{0, 1, 1, 2, 3} {
+		select {
+		case ke := <-kech:
+			if want := timeFromUnixMillis(first + minute*testKlineMinute); !ke.OpenTime.Equal(want) {
+				t.Errorf("kline %d opened at %v, want %v", i, ke.OpenTime, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("kline %d not received", i)
+		}
+	}
+}
+
+func TestDepthWebsocketSnapshot(t *testing.T) {
+	as := newTestStreamService(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/depth":
+			fmt.Fprint(w, `{"lastUpdateId":160,"bids":[["0.0024","10"]],"asks":[["0.0026","100"]]}`)
+		case "/ws/bnbbtc@depth":
+			c := upgradeTestWS(t, w, r)
+			if c == nil {
+				return
+			}
+			c.WriteMessage(websocket.TextMessage, []byte(`{"e":"depthUpdate","E":1600000000000,"s":"BNBBTC",`+
+				`"U":161,"u":162,"b":[["0.0024","0"]],"a":[]}`))
+			holdTestWS(c)
+		}
+	})
+
+	before := time.Now()
+	dech, _, err := as.DepthWebsocket(DepthWebsocketRequest{Symbol: "bnbbtc", WithSnapshot: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []*DepthEvent
+	for len(events) < 2 {
+		select {
+		case de := <-dech:
+			events = append(events, de)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("depth event %d not received", len(events))
+		}
+	}
+	snapshot, update := events[0], events[1]
+	if !snapshot.IsSnapshot || snapshot.Type != DepthSnapshotEventType || snapshot.UpdateID != 160 {
+		t.Errorf("unexpected snapshot event %+v", snapshot)
+	}
+	if snapshot.Time.Location() != time.UTC || snapshot.Time.Before(before.Truncate(time.Second)) {
+		t.Errorf("snapshot time %v not fetch time in UTC", snapshot.Time)
+	}
+	if update.IsSnapshot || update.Type != "depthUpdate" || update.FirstUpdateID != 161 {
+		t.Errorf("unexpected update event %+v", update)
+	}
+}
+
+func TestWSReadSkipsControlAndNonJSONFrames(t *testing.T) {
+	const trade = `{"e":"trade","E":1600000000000,"s":"BNBBTC","t":%d,"p":"0.001","q":"100",` +
+		`"T":1600000000000,"m":true,"M":true}`
+	as := newTestStreamService(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ws/bnbbtc@trade" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+			return
+		}
+		c := upgradeTestWS(t, w, r)
+		if c == nil {
+			return
+		}
+		c.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(trade, 1)))
+		c.WriteControl(websocket.PingMessage, []byte("ping"), time.Now().Add(time.Second))
+		c.WriteMessage(websocket.TextMessage, []byte(`{"result":null,"id":1}`))
+		c.WriteMessage(websocket.TextMessage, []byte("not json"))
+		c.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(trade, 2)))
+		holdTestWS(c)
+	})
+
+	tech, done, err := as.TradeWebsocket(TradeWebsocketRequest{Symbol: "bnbbtc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []uint64{1, 2} {
+		select {
+		case te := <-tech:
+			if te.ID != id {
+				t.Errorf("received trade %d, want %d", te.ID, id)
+			}
+		case <-done:
+			t.Fatalf("stream stopped before trade %d", id)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("trade %d not received", id)
+		}
+	}
+	select {
+	case err := <-as.StreamErrors():
+		t.Errorf("unexpected stream error %v", err)
+	case <-done:
+		t.Error("stream stopped after skipped frames")
+	default:
+	}
+}
+
+func TestStreamFixtures(t *testing.T) {
+	userData := func(as *apiService) (interface{}, error) {
+		aech, _, err := as.UserDataWebsocket(UserDataWebsocketRequest{ListenKey: "listen-key"})
+		return aech, err
+	}
+	testStreamFixtures(t, []streamFixture{
+		{
+			name:    "DepthWebsocket",
+			path:    "/ws/bnbbtc@depth",
+			fixture: "ws_depth.json",
+			start: func(as *apiService) (interface{}, error) {
+				dech, _, err := as.DepthWebsocket(DepthWebsocketRequest{Symbol: "BNBBTC"})
+				return dech, err
+			},
+			want: &DepthEvent{
+				WSEvent: WSEvent{
+					Type:   "depthUpdate",
+					Time:   timeFromUnixMillis(1672515782136),
+					Symbol: "BNBBTC",
+				},
+				FirstUpdateID: 157,
+				UpdateID:      160,
+				OrderBook: OrderBook{
+					Bids: []*Order{
+						{Price: 0.0024, Quantity: 10, RawPrice: "0.0024", RawQuantity: "10"},
+					},
+					Asks: []*Order{
+						{Price: 0.0026, Quantity: 100, RawPrice: "0.0026", RawQuantity: "100"},
+						{Price: 0.0027, Quantity: 0, RawPrice: "0.0027", RawQuantity: "0"},
+					},
+				},
+			},
+		},
+		{
+			name:    "KlineWebsocket",
+			path:    "/ws/bnbbtc@kline_1m",
+			fixture: "ws_kline.json",
+			start: func(as *apiService) (interface{}, error) {
+				kech, _, err := as.KlineWebsocket(KlineWebsocketRequest{Symbol: "BNBBTC", Interval: Minute})
+				return kech, err
+			},
+			want: &KlineEvent{
+				WSEvent: WSEvent{
+					Type:   "kline",
+					Time:   timeFromUnixMillis(1672515782136),
+					Symbol: "BNBBTC",
+				},
+				Interval:     Minute,
+				FirstTradeID: 100,
+				LastTradeID:  200,
+				Final:        false,
+				Kline: Kline{
+					OpenTime:                 timeFromUnixMillis(1672515780000),
+					Open:                     0.001,
+					High:                     0.0025,
+					Low:                      0.0015,
+					Close:                    0.002,
+					Volume:                   1000,
+					CloseTime:                timeFromUnixMillis(1672515839999),
+					QuoteAssetVolume:         1,
+					NumberOfTrades:           100,
+					TakerBuyBaseAssetVolume:  500,
+					TakerBuyQuoteAssetVolume: 0.5,
+
+					RawOpen:                     "0.0010",
+					RawHigh:                     "0.0025",
+					RawLow:                      "0.0015",
+					RawClose:                    "0.0020",
+					RawVolume:                   "1000",
+					RawQuoteAssetVolume:         "1.0000",
+					RawTakerBuyBaseAssetVolume:  "500",
+					RawTakerBuyQuoteAssetVolume: "0.500",
+				},
+			},
+		},
+		{
+			name:    "AggTradeWebsocket",
+			path:    "/ws/bnbbtc@aggTrade",
+			fixture: "ws_agg_trade.json",
+			start: func(as *apiService) (interface{}, error) {
+				aech, _, err := as.AggTradeWebsocket(AggTradeWebsocketRequest{Symbol: "BNBBTC"})
+				return aech, err
+			},
+			want: &AggTradeEvent{
+				WSEvent: WSEvent{
+					Type:   "aggTrade",
+					Time:   timeFromUnixMillis(1672515782136),
+					Symbol: "BNBBTC",
+				},
+				AggTrade: AggTrade{
+					ID:             12345,
+					Price:          0.001,
+					Quantity:       100,
+					FirstTradeID:   100,
+					LastTradeID:    105,
+					Timestamp:      timeFromUnixMillis(1672515782136),
+					BuyerMaker:     true,
+					BestPriceMatch: true,
+					RawPrice:       "0.001",
+					RawQuantity:    "100",
+				},
+			},
+		},
+		{
+			name:    "TradeWebsocket",
+			path:    "/ws/bnbbtc@trade",
+			fixture: "ws_trade.json",
+			start: func(as *apiService) (interface{}, error) {
+				tech, _, err := as.TradeWebsocket(TradeWebsocketRequest{Symbol: "BNBBTC"})
+				return tech, err
+			},
+			want: &TradeEvent{
+				WSEvent: WSEvent{
+					Type:   "trade",
+					Time:   timeFromUnixMillis(1672515782136),
+					Symbol: "BNBBTC",
+				},
+				Trade: Trade{
+					ID:             12345,
+					Price:          0.001,
+					Quantity:       100,
+					BuyerId:        88,
+					SellerId:       50,
+					TradeTime:      timeFromUnixMillis(1672515782136),
+					BuyerMaker:     true,
+					BestPriceMatch: true,
+				},
+			},
+		},
+		{
+			name:    "outboundAccountInfo",
+			path:    "/ws/listen-key",
+			fixture: "ws_outbound_account_info.json",
+			start:   userData,
+			want: &AccountEvent{
+				WSEvent: WSEvent{
+					Type: "outboundAccountInfo",
+					Time: timeFromUnixMillis(1499405658849),
+				},
+				Account: Account{
+					CanTrade:    true,
+					CanWithdraw: true,
+					CanDeposit:  true,
+					Balances: []*Balance{
+						{Asset: "LTC", Free: 17366.18538083, RawFree: "17366.18538083", RawLocked: "0.00000000"},
+						{Asset: "BTC", Free: 10537.85314051, Locked: 2.19464093, RawFree: "10537.85314051", RawLocked: "2.19464093"},
+					},
+				},
+			},
+		},
+		{
+			name:    "outboundAccountPosition",
+			path:    "/ws/listen-key",
+			fixture: "ws_outbound_account_position.json",
+			start:   userData,
+			want: &AccountEvent{
+				WSEvent: WSEvent{
+					Type: "outboundAccountPosition",
+					Time: timeFromUnixMillis(1564034571105),
+				},
+				AccountPosition: &AccountPosition{
+					LastUpdateTime: timeFromUnixMillis(1564034571073),
+					Balances: []*Balance{
+						{Asset: "ETH", Free: 10000, RawFree: "10000.000000", RawLocked: "0.000000"},
+					},
+				},
+			},
+		},
+		{
+			name:    "listStatus",
+			path:    "/ws/listen-key",
+			fixture: "ws_list_status.json",
+			start:   userData,
+			want: &AccountEvent{
+				WSEvent: WSEvent{
+					Type:   "listStatus",
+					Time:   timeFromUnixMillis(1564035303637),
+					Symbol: "ETHBTC",
+				},
+				ListStatus: &ListStatus{
+					Symbol:            "ETHBTC",
+					OrderListID:       2,
+					ContingencyType:   "OCO",
+					ListStatusType:    "EXEC_STARTED",
+					ListOrderStatus:   "EXECUTING",
+					ListRejectReason:  "NONE",
+					ListClientOrderID: "F4QN4G8DlFATFlIUQ0cjdD",
+					TransactionTime:   timeFromUnixMillis(1564035303625),
+					Orders: []*ListStatusOrder{
+						{Symbol: "ETHBTC", OrderID: 17, ClientOrderID: "AJYsMjErWJesZvqlJCTUgL"},
+						{Symbol: "ETHBTC", OrderID: 18, ClientOrderID: "bfYPSQdLoqAJeNrOr9adzq"},
+					},
+				},
+			},
+		},
+		{
+			name:    "balanceUpdate",
+			path:    "/ws/listen-key",
+			fixture: "ws_balance_update.json",
+			start:   userData,
+			want: &AccountEvent{
+				WSEvent: WSEvent{
+					Type: "balanceUpdate",
+					Time: timeFromUnixMillis(1573200697110),
+				},
+				BalanceUpdate: &BalanceUpdate{
+					Asset:     "BTC",
+					Delta:     100,
+					ClearTime: timeFromUnixMillis(1573200697068),
+				},
+			},
+		},
+		{
+			name:    "executionReport",
+			path:    "/ws/listen-key",
+			fixture: "execution_report_trade.json",
+			start:   userData,
+			want: &AccountEvent{
+				WSEvent: WSEvent{
+					Type:   "executionReport",
+					Time:   timeFromUnixMillis(1499405658658),
+					Symbol: "ETHBTC",
+				},
+				ExecutionReport: &ExecutionReportEvent{
+					Type:                     "executionReport",
+					EventTime:                1499405658658,
+					Symbol:                   "ETHBTC",
+					ClientOrderId:            "mUvoqJxFIILMdfAW5iGSOW",
+					Side:                     "BUY",
+					OrderType:                "LIMIT",
+					TimeInForce:              "GTC",
+					Quantity:                 1,
+					Price:                    0.1026441,
+					CurrentExecutionType:     "TRADE",
+					CurrentOrderStatus:       "FILLED",
+					OrderRejectReason:        "NONE",
+					OrderId:                  4293153,
+					LastExecutedQuantity:     1,
+					CumulativeFilledQuantity: 1,
+					LastExecutedPrice:        0.1026441,
+					CommissionAmount:         0.00075,
+					CommissionAsset:          "BNB",
+					TransactionTime:          1499405658657,
+					TradeId:                  12345,
+					O:                        1499405658657,
+					Z:                        0.1026441,
+					OrderListID:              -1,
+				},
+			},
+		},
+		{
+			name:    "listenKeyExpired",
+			path:    "/ws/listen-key",
+			fixture: "ws_listen_key_expired.json",
+			start:   userData,
+			want: &AccountEvent{
+				WSEvent: WSEvent{
+					Type: "listenKeyExpired",
+					Time: timeFromUnixMillis(1699596037418),
+				},
+				ListenKeyExpired: &ListenKeyExpired{
+					ListenKey: "OfYGbUzi3PraNagEkdKuFwUHn48brFsItTdsuiIXrucEvD0rhRXZ7I6URWfE8YE8",
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,194 @@
+package binance
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// wsAPIIntParams are sent as JSON numbers and wsAPIBoolParams as JSON
+// booleans, other params are sent as strings.
+var (
+	wsAPIIntParams = map[string]bool{
+		"timestamp":     true,
+		"recvWindow":    true,
+		"orderId":       true,
+		"trailingDelta": true,
+	}
+	wsAPIBoolParams = map[string]bool{
+		"omitZeroBalances": true,
+	}
+)
+
+type wsAPIClient struct {
+	as   *apiService
+	conn *wsConn
+	done chan struct{}
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	lastID  int64
+	pending map[int64]chan *wsAPIResponse
+}
+
+type wsAPIRequest struct {
+	ID     int64                  `json:"id"`
+	Method string                 `json:"method"`
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+type wsAPIResponse struct {
+	ID     int64           `json:"id"`
+	Status int             `json:"status"`
+	Result json.RawMessage `json:"result"`
+	Error  *Error          `json:"error"`
+}
+
+func (as *apiService) NewWSAPIClient() (WSAPIClient, error) {
+	as = as.streamService()
+	url := "wss://ws-api.binance.com:443/ws-api/v3"
+
+	c, err := as.wsDial(url)
+	if err != nil {
+		as.stopStream()
+		return nil, err
+	}
+	wc := &wsAPIClient{
+		as:      as,
+		conn:    c,
+		pending: make(map[int64]chan *wsAPIResponse),
+	}
+	wc.done = as.wsServeConn(c, url, nil, wc.handle)
+	return wc, nil
+}
+
+func (wc *wsAPIClient) handle(message []byte) error {
+	res := &wsAPIResponse{}
+	if err := json.Unmarshal(message, res); err != nil {
+		return errors.Wrap(err, "wsAPIResponse unmarshal failed")
+	}
+	wc.mu.Lock()
+	resCh, ok := wc.pending[res.ID]
+	delete(wc.pending, res.ID)
+	wc.mu.Unlock()
+	if ok {
+		resCh <- res
+	}
+	return nil
+}
+
+// call signs params if requested, sends request and waits for response with
+// the same id.
+func (wc *wsAPIClient) call(method string, params map[string]string, sign bool) (json.RawMessage, error) {
+	if sign {
+		params["apiKey"] = wc.as.APIKey
+		params["signature"] = wc.as.Signer.Sign([]byte(wsAPIPayload(params)))
+	}
+	reqParams := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		switch {
+		case wsAPIIntParams[k]:
+			reqParams[k] = json.Number(v)
+		case wsAPIBoolParams[k]:
+			reqParams[k] = v == "true"
+		default:
+			reqParams[k] = v
+		}
+	}
+
+	wc.mu.Lock()
+	wc.lastID++
+	id := wc.lastID
+	resCh := make(chan *wsAPIResponse, 1)
+	wc.pending[id] = resCh
+	wc.mu.Unlock()
+
+	wc.writeMu.Lock()
+	err := wc.conn.WriteJSON(&wsAPIRequest{
+		ID:     id,
+		Method: method,
+		Params: reqParams,
+	})
+	wc.writeMu.Unlock()
+	if err != nil {
+		wc.forget(id)
+		return nil, errors.Wrap(err, "websocket API request write failed")
+	}
+
+	timer := time.NewTimer(streamRequestTimeout)
+	defer timer.Stop()
+	select {
+	case res := <-resCh:
+		if res.Error != nil {
+			wc.as.observer.APIError(res.Error.Code)
+			return nil, res.Error
+		}
+		if res.Status != 200 {
+			return nil, errors.Errorf("websocket API request %s failed with status %d", method, res.Status)
+		}
+		return res.Result, nil
+	case <-wc.done:
+		return nil, errors.New("websocket API connection closed")
+	case <-timer.C:
+		wc.forget(id)
+		return nil, errors.Errorf("websocket API request %s timed out", method)
+	}
+}
+
+func (wc *wsAPIClient) forget(id int64) {
+	wc.mu.Lock()
+	delete(wc.pending, id)
+	wc.mu.Unlock()
+}
+
+// wsAPIPayload returns signature payload, params sorted by name and joined
+// without escaping.
+func wsAPIPayload(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=" + params[k]
+	}
+	return strings.Join(pairs, "&")
+}
+
+func (wc *wsAPIClient) NewOrder(or NewOrderRequest) (*ProcessedOrder, error) {
+	params, err := wc.as.newOrderParams(or)
+	if err != nil {
+		return nil, err
+	}
+	result, err := wc.call("order.place", params, true)
+	if err != nil {
+		return nil, err
+	}
+	return processedOrderFromJSON(result)
+}
+
+func (wc *wsAPIClient) CancelOrder(cor CancelOrderRequest) (*CanceledOrder, error) {
+	result, err := wc.call("order.cancel", cancelOrderParams(cor), true)
+	if err != nil {
+		return nil, err
+	}
+	return canceledOrderFromJSON(result)
+}
+
+func (wc *wsAPIClient) Account(ar AccountRequest) (*Account, error) {
+	result, err := wc.call("account.status", accountParams(ar), true)
+	if err != nil {
+		return nil, err
+	}
+	return accountFromJSON(result)
+}
+
+func (wc *wsAPIClient) Done() <-chan struct{} {
+	return wc.done
+}
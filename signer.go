@@ -1,9 +1,18 @@
 package binance
 
 import (
+	"crypto"
+	"crypto/ed25519"
 	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
 	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
 	"encoding/hex"
+	"encoding/pem"
+
+	"github.com/pkg/errors"
 )
 
 // Signer signs provided payloads.
@@ -12,7 +21,7 @@ type Signer interface {
 	Sign(payload []byte) string
 }
 
-// HmacSigner uses HMAC SHA256 for signing payloads.
+// HmacSigner uses HMAC SHA256 for signing payloads. Signature is hex encoded.
 type HmacSigner struct {
 	Key []byte
 }
@@ -23,3 +32,71 @@ func (hs *HmacSigner) Sign(payload []byte) string {
 	mac.Write(payload)
 	return hex.EncodeToString(mac.Sum(nil))
 }
+
+// Ed25519Signer uses Ed25519 for signing payloads.
+type Ed25519Signer struct {
+	Key ed25519.PrivateKey
+}
+
+// Sign signs provided payload and returns base64 encoded signature.
+func (es *Ed25519Signer) Sign(payload []byte) string {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(es.Key, payload))
+}
+
+// NewEd25519Signer creates Ed25519Signer from PEM encoded PKCS #8 private key.
+func NewEd25519Signer(pemKey []byte) (*Ed25519Signer, error) {
+	key, err := parsePrivateKeyPEM(pemKey)
+	if err != nil {
+		return nil, err
+	}
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not Ed25519")
+	}
+	return &Ed25519Signer{Key: edKey}, nil
+}
+
+// RSASigner uses RSASSA-PKCS1-v1_5 with SHA256 for signing payloads.
+type RSASigner struct {
+	Key *rsa.PrivateKey
+}
+
+// Sign signs provided payload and returns base64 encoded signature. Empty
+// string is returned if key is unable to sign, e.g. it's too short.
+func (rs *RSASigner) Sign(payload []byte) string {
+	sum := sha256.Sum256(payload)
+	sig, err := rsa.SignPKCS1v15(rand.Reader, rs.Key, crypto.SHA256, sum[:])
+	if err != nil {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(sig)
+}
+
+// NewRSASigner creates RSASigner from PEM encoded PKCS #8 or PKCS #1 private
+// key.
+func NewRSASigner(pemKey []byte) (*RSASigner, error) {
+	key, err := parsePrivateKeyPEM(pemKey)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not RSA")
+	}
+	return &RSASigner{Key: rsaKey}, nil
+}
+
+func parsePrivateKeyPEM(pemKey []byte) (interface{}, error) {
+	block, _ := pem.Decode(pemKey)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse private key")
+	}
+	return key, nil
+}
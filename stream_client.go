@@ -0,0 +1,38 @@
+package binance
+
+import (
+	"encoding/json"
+)
+
+// StreamClient manages streams of single websocket connection, so streams
+// can be added and removed without reconnecting.
+//
+// Stream names are the same as in stream URLs, e.g. "bnbbtc@depth" or
+// "bnbbtc@kline_1m". Messages of all subscribed streams are delivered by
+// Messages, which has to be consumed for subscription calls to complete.
+// Cancel service context to close the connection.
+type StreamClient interface {
+	// Subscribe adds streams to the connection.
+	Subscribe(streams ...string) error
+	// Unsubscribe removes streams from the connection.
+	Unsubscribe(streams ...string) error
+	// ListSubscriptions returns streams subscribed on the connection.
+	ListSubscriptions() ([]string, error)
+	// Messages returns channel of messages of subscribed streams.
+	Messages() <-chan *StreamMessage
+	// Done returns channel closed when connection is closed.
+	Done() <-chan struct{}
+}
+
+// StreamMessage represents single message of subscribed stream.
+type StreamMessage struct {
+	// Stream is name of the stream, e.g. "bnbbtc@depth".
+	Stream string
+	// Data is raw event payload.
+	Data json.RawMessage
+}
+
+// NewStreamClient opens websocket connection with no streams subscribed.
+func (b *binance) NewStreamClient() (StreamClient, error) {
+	return b.Service.NewStreamClient()
+}
@@ -0,0 +1,171 @@
+package binance
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StreamStatus describes single open websocket connection.
+type StreamStatus struct {
+	// URL is websocket URL of the connection.
+	URL string
+	// Streams are names of streams delivered by the connection, e.g.
+	// "bnbbtc@depth", as subscribed at the moment.
+	Streams     []string
+	ConnectedAt time.Time
+	// LastMessage is time the last message was received, zero if there was
+	// none yet.
+	LastMessage time.Time
+	Messages    int64
+	// Reconnects counts how many times the stream was reconnected since it
+	// was started.
+	Reconnects int
+}
+
+// streamRegistry tracks open websocket connections and cancel functions of
+// streams by done channels returned for them.
+type streamRegistry struct {
+	mu      sync.Mutex
+	entries map[<-chan struct{}]*StreamStatus
+	cancels map[<-chan struct{}]context.CancelFunc
+}
+
+func newStreamRegistry() *streamRegistry {
+	return &streamRegistry{
+		entries: make(map[<-chan struct{}]*StreamStatus),
+		cancels: make(map[<-chan struct{}]context.CancelFunc),
+	}
+}
+
+func (r *streamRegistry) add(done <-chan struct{}, rawURL string, cancel context.CancelFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[done] = &StreamStatus{
+		URL:         rawURL,
+		Streams:     streamNames(rawURL),
+		ConnectedAt: time.Now(),
+	}
+	if cancel != nil {
+		r.cancels[done] = cancel
+	}
+}
+
+// addCloser registers cancel of stream not tracked as connection, e.g. one
+// reconnecting over several connections.
+func (r *streamRegistry) addCloser(done <-chan struct{}, cancel context.CancelFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cancel != nil {
+		r.cancels[done] = cancel
+	}
+}
+
+func (r *streamRegistry) remove(done <-chan struct{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.entries, done)
+	delete(r.cancels, done)
+}
+
+// close cancels stream of done, if it's known.
+func (r *streamRegistry) close(done <-chan struct{}) {
+	r.mu.Lock()
+	cancel := r.cancels[done]
+	r.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// received records message received by connection of done.
+func (r *streamRegistry) received(done <-chan struct{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if e, ok := r.entries[done]; ok {
+		e.LastMessage = time.Now()
+		e.Messages++
+	}
+}
+
+func (r *streamRegistry) addReconnects(done <-chan struct{}, reconnects int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if e, ok := r.entries[done]; ok {
+		e.Reconnects += reconnects
+	}
+}
+
+// subscribed adds and removes stream names of connection of done.
+func (r *streamRegistry) subscribed(done <-chan struct{}, added, removed []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.entries[done]
+	if !ok {
+		return
+	}
+	gone := make(map[string]bool)
+	for _, s := range added {
+		gone[s] = true
+	}
+	for _, s := range removed {
+		gone[s] = true
+	}
+	streams := make([]string, 0, len(e.Streams)+len(added))
+	for _, s := range e.Streams {
+		if !gone[s] {
+			streams = append(streams, s)
+		}
+	}
+	e.Streams = append(streams, added...)
+}
+
+// closeAll cancels all known streams and returns their done channels.
+func (r *streamRegistry) closeAll() []<-chan struct{} {
+	r.mu.Lock()
+	cancels := make([]context.CancelFunc, 0, len(r.cancels))
+	dones := make([]<-chan struct{}, 0, len(r.cancels))
+	for done, cancel := range r.cancels {
+		cancels = append(cancels, cancel)
+		dones = append(dones, done)
+	}
+	r.mu.Unlock()
+	for _, cancel := range cancels {
+		cancel()
+	}
+	return dones
+}
+
+// snapshot returns copy of statuses ordered by connection time.
+func (r *streamRegistry) snapshot() []StreamStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	statuses := make([]StreamStatus, 0, len(r.entries))
+	for _, e := range r.entries {
+		s := *e
+		s.Streams = append([]string(nil), e.Streams...)
+		statuses = append(statuses, s)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].ConnectedAt.Before(statuses[j].ConnectedAt)
+	})
+	return statuses
+}
+
+// streamNames returns names of streams of raw or combined stream URL.
+func streamNames(rawURL string) []string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil
+	}
+	if streams := u.Query().Get("streams"); streams != "" {
+		return strings.Split(streams, "/")
+	}
+	if strings.HasPrefix(u.Path, "/ws/") {
+		return strings.Split(strings.TrimPrefix(u.Path, "/ws/"), "/")
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package binance
+
+import "time"
+
+// SubAccountListRequest represents SubAccountList request data.
+type SubAccountListRequest struct {
+	Email      string
+	IsFreeze   *bool
+	Page       int
+	Limit      int
+	RecvWindow time.Duration
+	Timestamp  time.Time
+}
+
+// SubAccount represents sub-account information.
+type SubAccount struct {
+	Email      string
+	IsFreeze   bool
+	CreateTime time.Time
+}
+
+// SubAccountList lists sub-accounts of master account.
+func (b *binance) SubAccountList(salr SubAccountListRequest) ([]*SubAccount, error) {
+	return b.Service.SubAccountList(salr)
+}
+
+// SubAccountTransferRequest represents SubAccountTransfer request data.
+//
+// FromEmail or ToEmail left empty means master account. Account types are
+// SPOT, USDT_FUTURE, COIN_FUTURE, MARGIN or ISOLATED_MARGIN and default to
+// SPOT when empty.
+type SubAccountTransferRequest struct {
+	FromEmail       string
+	ToEmail         string
+	FromAccountType string
+	ToAccountType   string
+	Asset           string
+	Amount          float64
+	RecvWindow      time.Duration
+	Timestamp       time.Time
+}
+
+// SubAccountTransferResult represents SubAccountTransfer result.
+type SubAccountTransferResult struct {
+	TranID int64
+}
+
+// SubAccountTransfer moves balance between master and sub-accounts.
+func (b *binance) SubAccountTransfer(satr SubAccountTransferRequest) (*SubAccountTransferResult, error) {
+	return b.Service.SubAccountTransfer(satr)
+}
+
+// SubAccountAssetsRequest represents SubAccountAssets request data.
+type SubAccountAssetsRequest struct {
+	Email      string
+	RecvWindow time.Duration
+	Timestamp  time.Time
+}
+
+// SubAccountAssets returns spot balances of sub-account.
+func (b *binance) SubAccountAssets(saar SubAccountAssetsRequest) ([]*Balance, error) {
+	return b.Service.SubAccountAssets(saar)
+}
@@ -4,12 +4,25 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-kit/kit/log/level"
 	"github.com/pkg/errors"
 )
 
+// NormalizeSymbol returns symbol in canonical form used by REST API, e.g.
+// "BTCUSDT" for "btcusdt" or " BtcUsdt". Service normalizes symbols of all
+// requests, so it's needed only to compare symbols on client side.
+func NormalizeSymbol(symbol string) string {
+	return strings.ToUpper(strings.TrimSpace(symbol))
+}
+
+// streamSymbol returns symbol in form used by websocket stream names.
+func streamSymbol(symbol string) string {
+	return strings.ToLower(NormalizeSymbol(symbol))
+}
+
 func floatFromString(raw interface{}) (float64, error) {
 	str, ok := raw.(string)
 	if !ok {
@@ -22,6 +35,61 @@ func floatFromString(raw interface{}) (float64, error) {
 	return flt, nil
 }
 
+// optionalFloat parses float of field which may be missing, e.g. depending
+// on response type, missing field is zero.
+func optionalFloat(str string) (float64, error) {
+	if str == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(str, 64)
+}
+
+// lenientFloat parses JSON value sent either as string or number, null and
+// empty string are parsed as 0.
+func lenientFloat(raw json.RawMessage) (float64, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return 0, nil
+	}
+	if raw[0] == '"' {
+		var str string
+		if err := json.Unmarshal(raw, &str); err != nil {
+			return 0, err
+		}
+		return optionalFloat(str)
+	}
+	return strconv.ParseFloat(string(raw), 64)
+}
+
+// lenientInt is integer field which Binance sends either as number or as
+// string, e.g. order and update ids, so that parsing doesn't break when
+// representation changes. null and empty string are parsed as 0.
+type lenientInt int64
+
+// UnmarshalJSON parses integer sent either as number or string.
+func (i *lenientInt) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*i = 0
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+		data = []byte(str)
+	}
+	if len(data) == 0 {
+		*i = 0
+		return nil
+	}
+	n, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("unable to parse as int: %s", data))
+	}
+	*i = lenientInt(n)
+	return nil
+}
+
 func intFromString(raw interface{}) (int, error) {
 	str, ok := raw.(string)
 	if !ok {
@@ -43,7 +111,7 @@ func timeFromUnixTimestampString(raw interface{}) (time.Time, error) {
 	if err != nil {
 		return time.Time{}, errors.Wrap(err, fmt.Sprintf("unable to parse as int: %s", str))
 	}
-	return time.Unix(0, ts*int64(time.Millisecond)), nil
+	return timeFromUnixMillis(ts), nil
 }
 
 func timeFromUnixTimestampFloat(raw interface{}) (time.Time, error) {
@@ -51,7 +119,17 @@ func timeFromUnixTimestampFloat(raw interface{}) (time.Time, error) {
 	if !ok {
 		return time.Time{}, errors.New(fmt.Sprintf("unable to parse, value not int64: %T", raw))
 	}
-	return time.Unix(0, int64(ts)*int64(time.Millisecond)), nil
+	return timeFromUnixMillis(int64(ts)), nil
+}
+
+// timeFromUnixMillis converts millisecond timestamp to time in UTC, so that
+// parsed times don't depend on timezone of the host. 0, sent by Binance for
+// times not set yet, becomes zero time.
+func timeFromUnixMillis(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ms*int64(time.Millisecond)).UTC()
 }
 
 func unixMillis(t time.Time) int64 {
@@ -68,5 +146,20 @@ func (as *apiService) handleError(textRes []byte) error {
 	if err := json.Unmarshal(textRes, err); err != nil {
 		return errors.Wrap(err, "error unmarshal failed")
 	}
+	as.observer.APIError(err.Code)
 	return err
 }
+
+// kahanSum accumulates floats with compensated summation, which keeps error
+// from growing with number of added values.
+type kahanSum struct {
+	sum float64
+	c   float64
+}
+
+func (k *kahanSum) add(v float64) {
+	y := v - k.c
+	t := k.sum + y
+	k.c = (t - k.sum) - y
+	k.sum = t
+}
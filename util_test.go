@@ -0,0 +1,186 @@
+package binance
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// checkUTC reports times not in UTC location.
+func checkUTC(t *testing.T, times map[string]time.Time) {
+	t.Helper()
+	for name, tm := range times {
+		if tm.IsZero() {
+			t.Errorf("%s not parsed", name)
+		} else if tm.Location() != time.UTC {
+			t.Errorf("%s = %v in %v, want UTC", name, tm, tm.Location())
+		}
+	}
+}
+
+func TestRESTTimesInUTC(t *testing.T) {
+	as := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/klines":
+			fmt.Fprintf(w, "[%s]", testKlineRow(1600000000000))
+		case "/api/v1/aggTrades":
+			fmt.Fprint(w, `[{"a":26129,"p":"0.01633102","q":"4.70443515","f":27781,"l":27781,`+
+				`"T":1498793709153,"m":true,"M":true}]`)
+		case "/api/v3/ticker/24hr":
+			fmt.Fprint(w, `{"symbol":"BNBBTC","priceChange":"-94.99","priceChangePercent":"-95.96",`+
+				`"weightedAvgPrice":"0.29","prevClosePrice":"0.10","lastPrice":"4.00","bidPrice":"4.00",`+
+				`"askPrice":"4.00","openPrice":"99.00","highPrice":"100.00","lowPrice":"0.10","volume":"8913.30",`+
+				`"openTime":1499783499040,"closeTime":1499869899040,"firstId":28385,"lastId":28460,"count":76}`)
+		case "/api/v3/order":
+			fmt.Fprint(w, `{"symbol":"BNBBTC","orderId":28,"clientOrderId":"6gCrw2kRUAF9CvJDGP16IP",`+
+				`"transactTime":1507725176595}`)
+		case "/sapi/v1/capital/deposit/hisrec":
+			fmt.Fprint(w, `[{"id":"1","amount":"0.00999800","coin":"PAXG","network":"ETH","status":1,`+
+				`"address":"0x788cabe9236ce061e5a892e1a59395a81fc8d62c","txId":"0xaad4","insertTime":1599621997000}]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"code":-1,"msg":"unknown endpoint"}`)
+		}
+	})
+
+	klines, err := as.Klines(KlinesRequest{Symbol: "BNBBTC", Interval: Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	aggTrades, err := as.AggTrades(AggTradesRequest{Symbol: "BNBBTC"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ticker, err := as.Ticker24(TickerRequest{Symbol: "BNBBTC"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	order, err := as.NewOrder(NewOrderRequest{
+		Symbol:    "BNBBTC",
+		Side:      SideBuy,
+		Type:      TypeMarket,
+		Quantity:  1,
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deposits, err := as.DepositHistory(HistoryRequest{Timestamp: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkUTC(t, map[string]time.Time{
+		"Kline.OpenTime":              klines[0].OpenTime,
+		"Kline.CloseTime":             klines[0].CloseTime,
+		"AggTrade.Timestamp":          aggTrades[0].Timestamp,
+		"Ticker24.OpenTime":           ticker.OpenTime,
+		"Ticker24.CloseTime":          ticker.CloseTime,
+		"ProcessedOrder.TransactTime": order.TransactTime,
+		"Deposit.InsertTime":          deposits[0].InsertTime,
+	})
+}
+
+func TestStreamTimesInUTC(t *testing.T) {
+	as := newTestStreamService(t, func(w http.ResponseWriter, r *http.Request) {
+		c := upgradeTestWS(t, w, r)
+		if c == nil {
+			return
+		}
+		switch r.URL.Path {
+		case "/ws/bnbbtc@kline_1m":
+			c.WriteMessage(websocket.TextMessage, []byte(testKlineEvent(1600000000000)))
+		case "/ws/bnbbtc@aggTrade":
+			c.WriteMessage(websocket.TextMessage, []byte(`{"e":"aggTrade","E":1600000000001,"s":"BNBBTC",`+
+				`"a":12345,"p":"0.001","q":"100","f":100,"l":105,"T":1600000000000,"m":true,"M":true}`))
+		case "/ws/bnbbtc@trade":
+			c.WriteMessage(websocket.TextMessage, []byte(`{"e":"trade","E":1600000000001,"s":"BNBBTC",`+
+				`"t":12345,"p":"0.001","q":"100","T":1600000000000,"m":true,"M":true}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		holdTestWS(c)
+	})
+
+	kech, _, err := as.KlineWebsocket(KlineWebsocketRequest{Symbol: "bnbbtc", Interval: Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	aech, _, err := as.AggTradeWebsocket(AggTradeWebsocketRequest{Symbol: "bnbbtc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tech, _, err := as.TradeWebsocket(TradeWebsocketRequest{Symbol: "bnbbtc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	times := make(map[string]time.Time)
+	for len(times) < 7 {
+		select {
+		case ke := <-kech:
+			times["KlineEvent.Time"] = ke.Time
+			times["KlineEvent.OpenTime"] = ke.OpenTime
+			times["KlineEvent.CloseTime"] = ke.CloseTime
+		case ae := <-aech:
+			times["AggTradeEvent.Time"] = ae.Time
+			times["AggTradeEvent.Timestamp"] = ae.Timestamp
+		case te := <-tech:
+			times["TradeEvent.Time"] = te.Time
+			times["TradeEvent.TradeTime"] = te.TradeTime
+		case <-time.After(5 * time.Second):
+			t.Fatalf("events not received, got %v", times)
+		}
+	}
+	checkUTC(t, times)
+}
+
+func TestTimestampPrecision(t *testing.T) {
+	// consecutive milliseconds of a realistic epoch must stay apart
+	const ms = int64(1499827319559)
+	as := newTestStreamService(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/time":
+			fmt.Fprintf(w, `{"serverTime":%d}`, ms)
+		case "/ws/bnbbtc@aggTrade":
+			c := upgradeTestWS(t, w, r)
+			if c == nil {
+				return
+			}
+			c.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(`{"e":"aggTrade","E":%d,"s":"BNBBTC",`+
+				`"a":12345,"p":"0.001","q":"100","f":100,"l":105,"T":%d,"m":true,"M":true}`, ms+1, ms)))
+			holdTestWS(c)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"code":-1,"msg":"unknown endpoint"}`)
+		}
+	})
+
+	serverTime, err := as.Time()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ech, _, err := as.AggTradeWebsocket(AggTradeWebsocketRequest{Symbol: "bnbbtc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ae *AggTradeEvent
+	select {
+	case ae = <-ech:
+	case <-time.After(5 * time.Second):
+		t.Fatal("event not received")
+	}
+	for name, tt := range map[string]struct {
+		got  time.Time
+		want int64
+	}{
+		"Time":                    {serverTime, ms},
+		"AggTradeEvent.Time":      {ae.Time, ms + 1},
+		"AggTradeEvent.Timestamp": {ae.Timestamp, ms},
+	} {
+		if got := tt.got.UnixNano(); got != tt.want*int64(time.Millisecond) {
+			t.Errorf("%s = %d ns, want %d ms", name, got, tt.want)
+		}
+	}
+}
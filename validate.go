@@ -0,0 +1,92 @@
+package binance
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Validate checks that request has fields required by its order type and
+// no fields forbidden by it, so that common mistakes are reported before
+// request is sent instead of by -1013 errors.
+//
+// Only field combinations are checked, filters of the symbol (e.g. tick or
+// lot size) are left to Binance.
+func (or NewOrderRequest) Validate() error {
+	if or.Symbol == "" {
+		return errors.New("invalid order: Symbol is required")
+	}
+	if or.Side != SideBuy && or.Side != SideSell {
+		return errors.Errorf("invalid order: unknown Side %q", or.Side)
+	}
+	if or.Quantity <= 0 {
+		return errors.Errorf("invalid order: Quantity must be positive, got %v", or.Quantity)
+	}
+	if or.Price < 0 || or.StopPrice < 0 || or.IcebergQty < 0 || or.TrailingDelta < 0 {
+		return errors.New("invalid order: Price, StopPrice, TrailingDelta and IcebergQty can't be negative")
+	}
+
+	switch or.Type {
+	case TypeLimit:
+		if or.Price == 0 {
+			return errors.New("invalid LIMIT order: Price is required")
+		}
+		if or.TimeInForce == "" {
+			return errors.New("invalid LIMIT order: TimeInForce is required")
+		}
+	case TypeLimitMaker:
+		if or.Price == 0 {
+			return errors.New("invalid LIMIT_MAKER order: Price is required")
+		}
+		if or.TimeInForce != "" {
+			return errors.New("invalid LIMIT_MAKER order: TimeInForce is not allowed")
+		}
+	case TypeStopLoss, TypeTakeProfit:
+		if or.StopPrice == 0 && or.TrailingDelta == 0 {
+			return errors.Errorf("invalid %s order: StopPrice or TrailingDelta is required", or.Type)
+		}
+		if or.Price != 0 {
+			return errors.Errorf("invalid %s order: Price is not allowed", or.Type)
+		}
+		if or.TimeInForce != "" {
+			return errors.Errorf("invalid %s order: TimeInForce is not allowed", or.Type)
+		}
+	case TypeStopLossLimit, TypeTakeProfitLimit:
+		if or.Price == 0 {
+			return errors.Errorf("invalid %s order: Price is required", or.Type)
+		}
+		if or.StopPrice == 0 && or.TrailingDelta == 0 {
+			return errors.Errorf("invalid %s order: StopPrice or TrailingDelta is required", or.Type)
+		}
+		if or.TimeInForce == "" {
+			return errors.Errorf("invalid %s order: TimeInForce is required", or.Type)
+		}
+	case TypeMarket:
+		if or.Price != 0 {
+			return errors.New("invalid MARKET order: Price is not allowed")
+		}
+		if or.TimeInForce != "" {
+			return errors.New("invalid MARKET order: TimeInForce is not allowed")
+		}
+		if or.IcebergQty != 0 {
+			return errors.New("invalid MARKET order: IcebergQty is not allowed")
+		}
+	case "":
+		return errors.New("invalid order: Type is required")
+	}
+	if or.TrailingDelta != 0 && !isStopOrderType(or.Type) {
+		return errors.Errorf("invalid %s order: TrailingDelta is not allowed", or.Type)
+	}
+	if or.IcebergQty != 0 && or.TimeInForce != "" && or.TimeInForce != GTC {
+		return errors.New("invalid order: IcebergQty requires GTC TimeInForce")
+	}
+	return nil
+}
+
+// isStopOrderType reports whether orders of type t are triggered by stop
+// price.
+func isStopOrderType(t OrderType) bool {
+	switch t {
+	case TypeStopLoss, TypeStopLossLimit, TypeTakeProfit, TypeTakeProfitLimit:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,180 @@
+package binance
+
+import "time"
+
+// AccountSnapshotType represents wallet type of account snapshots.
+type AccountSnapshotType string
+
+var (
+	SnapshotSpot    = AccountSnapshotType("SPOT")
+	SnapshotMargin  = AccountSnapshotType("MARGIN")
+	SnapshotFutures = AccountSnapshotType("FUTURES")
+)
+
+// AccountSnapshotRequest represents AccountSnapshot request data.
+//
+// Limit is number of days between 7 and 30, 7 by default.
+type AccountSnapshotRequest struct {
+	Type       AccountSnapshotType
+	StartTime  time.Time
+	EndTime    time.Time
+	Limit      int
+	RecvWindow time.Duration
+	Timestamp  time.Time
+}
+
+// AccountSnapshot represents daily snapshots of wallet balances.
+type AccountSnapshot struct {
+	Snapshots []*DailySnapshot
+}
+
+// DailySnapshot represents wallet balances at the end of a day.
+//
+// Fields are filled depending on snapshot Type: Balances for spot, margin
+// levels and UserAssets for margin, FuturesAssets and Positions for futures.
+type DailySnapshot struct {
+	Type                string
+	UpdateTime          time.Time
+	TotalAssetOfBTC     float64
+	Balances            []*Balance
+	MarginLevel         float64
+	TotalLiabilityOfBTC float64
+	TotalNetAssetOfBTC  float64
+	UserAssets          []*MarginSnapshotAsset
+	FuturesAssets       []*FuturesSnapshotAsset
+	Positions           []*FuturesSnapshotPosition
+}
+
+// MarginSnapshotAsset represents margin wallet balance of asset.
+type MarginSnapshotAsset struct {
+	Asset    string
+	Borrowed float64
+	Free     float64
+	Interest float64
+	Locked   float64
+	NetAsset float64
+}
+
+// FuturesSnapshotAsset represents futures wallet balance of asset.
+type FuturesSnapshotAsset struct {
+	Asset         string
+	MarginBalance float64
+	WalletBalance float64
+}
+
+// FuturesSnapshotPosition represents futures position.
+type FuturesSnapshotPosition struct {
+	Symbol           string
+	EntryPrice       float64
+	MarkPrice        float64
+	PositionAmt      float64
+	UnRealizedProfit float64
+}
+
+// AccountSnapshot returns daily snapshots of wallet balances.
+func (b *binance) AccountSnapshot(asr AccountSnapshotRequest) (*AccountSnapshot, error) {
+	return b.Service.AccountSnapshot(asr)
+}
+
+// TransferType represents direction of universal transfer between wallets.
+type TransferType string
+
+var (
+	TransferMainUMFuture    = TransferType("MAIN_UMFUTURE")
+	TransferMainCMFuture    = TransferType("MAIN_CMFUTURE")
+	TransferMainMargin      = TransferType("MAIN_MARGIN")
+	TransferMainFunding     = TransferType("MAIN_FUNDING")
+	TransferUMFutureMain    = TransferType("UMFUTURE_MAIN")
+	TransferUMFutureMargin  = TransferType("UMFUTURE_MARGIN")
+	TransferCMFutureMain    = TransferType("CMFUTURE_MAIN")
+	TransferCMFutureMargin  = TransferType("CMFUTURE_MARGIN")
+	TransferMarginMain      = TransferType("MARGIN_MAIN")
+	TransferMarginUMFuture  = TransferType("MARGIN_UMFUTURE")
+	TransferMarginCMFuture  = TransferType("MARGIN_CMFUTURE")
+	TransferMarginFunding   = TransferType("MARGIN_FUNDING")
+	TransferFundingMain     = TransferType("FUNDING_MAIN")
+	TransferFundingMargin   = TransferType("FUNDING_MARGIN")
+	TransferFundingUMFuture = TransferType("FUNDING_UMFUTURE")
+	TransferUMFutureFunding = TransferType("UMFUTURE_FUNDING")
+)
+
+// UniversalTransferRequest represents UniversalTransfer request data.
+//
+// FromSymbol and ToSymbol identify isolated margin account, they're needed
+// only by transfers from or to isolated margin.
+type UniversalTransferRequest struct {
+	Type       TransferType
+	Asset      string
+	Amount     float64
+	FromSymbol string
+	ToSymbol   string
+	RecvWindow time.Duration
+	Timestamp  time.Time
+}
+
+// TransferResult represents result of transfer between wallets.
+type TransferResult struct {
+	TranID int64
+}
+
+// UniversalTransfer moves asset between wallets, e.g. from spot to futures.
+func (b *binance) UniversalTransfer(utr UniversalTransferRequest) (*TransferResult, error) {
+	return b.Service.UniversalTransfer(utr)
+}
+
+// FundingAssetsRequest represents FundingAssets and UserAssets request data.
+// Empty Asset returns all assets with positive balance.
+type FundingAssetsRequest struct {
+	Asset            string
+	NeedBTCValuation bool
+	RecvWindow       time.Duration
+	Timestamp        time.Time
+}
+
+// FundingAsset represents wallet balance of asset. BTCValuation is filled
+// only if requested by NeedBTCValuation.
+type FundingAsset struct {
+	Asset        string
+	Free         float64
+	Locked       float64
+	Freeze       float64
+	Withdrawing  float64
+	BTCValuation float64
+}
+
+// FundingAssets returns funding wallet balances.
+func (b *binance) FundingAssets(far FundingAssetsRequest) ([]*FundingAsset, error) {
+	return b.Service.FundingAssets(far)
+}
+
+// UserAssets returns spot wallet balances including frozen and withdrawing
+// amounts.
+func (b *binance) UserAssets(far FundingAssetsRequest) ([]*FundingAsset, error) {
+	return b.Service.UserAssets(far)
+}
+
+// DustEstimate represents assets convertible to BNB and estimated result
+// of their conversion.
+type DustEstimate struct {
+	Details            []*DustAsset
+	TotalTransferBTC   float64
+	TotalTransferBNB   float64
+	DribbletPercentage float64
+}
+
+// DustAsset represents small balance of asset convertible to BNB.
+type DustAsset struct {
+	Asset            string
+	AssetFullName    string
+	AmountFree       float64
+	ToBTC            float64
+	ToBNB            float64
+	ToBNBOffExchange float64
+	Exchange         float64
+}
+
+// DustEstimate returns assets convertible to BNB with BTC and BNB estimates,
+// e.g. to preview conversion of dust.
+func (b *binance) DustEstimate() (*DustEstimate, error) {
+	return b.Service.DustEstimate()
+}
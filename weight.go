@@ -0,0 +1,93 @@
+package binance
+
+// endpointWeights maps REST endpoints to request weights published by
+// Binance. Endpoints whose weight depends on params hold weight of request
+// with default params.
+var endpointWeights = map[string]int{
+	"GET api/v1/ping":                             1,
+	"GET api/v1/time":                             1,
+	"GET api/v1/exchangeInfo":                     10,
+	"GET api/v1/depth":                            1,
+	"GET api/v1/historicalTrades":                 5,
+	"GET api/v1/aggTrades":                        1,
+	"GET api/v1/klines":                           1,
+	"GET api/v3/uiKlines":                         1,
+	"GET api/v3/ticker/24hr":                      1,
+	"GET api/v1/ticker/allPrices":                 2,
+	"GET api/v1/ticker/allBookTickers":            2,
+	"POST api/v3/order":                           1,
+	"POST api/v3/order/test":                      1,
+	"GET api/v3/order":                            2,
+	"DELETE api/v3/order":                         1,
+	"POST api/v3/order/cancelReplace":             1,
+	"GET api/v3/openOrders":                       3,
+	"GET api/v3/allOrders":                        10,
+	"GET api/v3/openOrderList":                    3,
+	"GET api/v3/allOrderList":                     10,
+	"GET api/v3/account":                          10,
+	"GET api/v3/myTrades":                         10,
+	"POST api/v1/userDataStream":                  1,
+	"PUT api/v1/userDataStream":                   1,
+	"DELETE api/v1/userDataStream":                1,
+	"POST sapi/v1/userDataStream":                 1,
+	"PUT sapi/v1/userDataStream":                  1,
+	"DELETE sapi/v1/userDataStream":               1,
+	"POST sapi/v1/userDataStream/isolated":        1,
+	"PUT sapi/v1/userDataStream/isolated":         1,
+	"DELETE sapi/v1/userDataStream/isolated":      1,
+	"POST sapi/v1/capital/withdraw/apply":         900,
+	"GET sapi/v1/capital/deposit/hisrec":          1,
+	"GET sapi/v1/capital/withdraw/history":        1,
+	"GET sapi/v1/accountSnapshot":                 2400,
+	"POST sapi/v1/asset/transfer":                 1,
+	"POST sapi/v1/asset/dust-btc":                 1,
+	"POST sapi/v1/asset/get-funding-asset":        1,
+	"POST sapi/v3/asset/getUserAsset":             5,
+	"GET sapi/v1/simple-earn/flexible/list":       150,
+	"POST sapi/v1/simple-earn/flexible/subscribe": 1,
+	"POST sapi/v1/simple-earn/flexible/redeem":    1,
+	"GET sapi/v1/sub-account/list":                1,
+	"GET sapi/v3/sub-account/assets":              1,
+	"POST sapi/v1/sub-account/universalTransfer":  1,
+	"GET fapi/v1/exchangeInfo":                    1,
+	"GET fapi/v1/klines":                          5,
+	"GET fapi/v1/fundingRate":                     1,
+	"POST fapi/v1/order":                          1,
+	"GET fapi/v2/account":                         5,
+	"GET fapi/v2/positionRisk":                    5,
+}
+
+// Weight returns request weight of endpoint given as method and path, e.g.
+// "GET api/v3/account", or zero when endpoint is unknown.
+//
+// Weight of endpoints depending on params is one of request with default
+// params: OrderBook with limit of 100 and Ticker24 and OpenOrders of single
+// symbol. OrderBookWeight and Ticker24Weight return weight for other params,
+// OpenOrders of all symbols weighs 40.
+func Weight(endpoint string) int {
+	return endpointWeights[endpoint]
+}
+
+// OrderBookWeight returns weight of OrderBook request with limit, zero means
+// default limit. It returns zero for limit not allowed by API.
+func OrderBookWeight(limit int) int {
+	if limit == 0 {
+		limit = 100
+	}
+	return orderBookWeights[limit]
+}
+
+// Ticker24Weight returns weight of 24 hour ticker request of symbols count,
+// zero means request of all symbols, sent with neither symbol nor symbols.
+func Ticker24Weight(symbols int) int {
+	switch {
+	case symbols == 0:
+		return 40
+	case symbols <= 20:
+		return 1
+	case symbols <= 100:
+		return 20
+	default:
+		return 40
+	}
+}
@@ -0,0 +1,22 @@
+package binance
+
+// WSAPIClient places and queries orders over websocket API, which avoids
+// establishing HTTP request for every call and so lowers latency.
+//
+// Requests are signed the same way as REST ones and are safe for concurrent
+// use. Cancel service context to close the connection.
+type WSAPIClient interface {
+	// NewOrder places new order.
+	NewOrder(or NewOrderRequest) (*ProcessedOrder, error)
+	// CancelOrder cancels order.
+	CancelOrder(cor CancelOrderRequest) (*CanceledOrder, error)
+	// Account returns account data.
+	Account(ar AccountRequest) (*Account, error)
+	// Done returns channel closed when connection is closed.
+	Done() <-chan struct{}
+}
+
+// NewWSAPIClient opens websocket API connection.
+func (b *binance) NewWSAPIClient() (WSAPIClient, error) {
+	return b.Service.NewWSAPIClient()
+}